@@ -3,6 +3,7 @@ package ast
 import (
 	"bytes"
 	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/Jamess-Lucass/interpreter-go/token"
@@ -11,6 +12,8 @@ import (
 type Node interface {
 	TokenLiteral() string
 	String() string
+	Pos() token.Position // position of the first character of the node
+	End() token.Position // position immediately after the node
 }
 
 type Statement interface {
@@ -37,6 +40,20 @@ func (p *Program) TokenLiteral() string {
 	return ""
 }
 
+func (p *Program) Pos() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[0].Pos()
+	}
+
+	return token.Position{}
+}
+func (p *Program) End() token.Position {
+	if len(p.Statements) > 0 {
+		return p.Statements[len(p.Statements)-1].End()
+	}
+
+	return token.Position{}
+}
 func (p *Program) String() string {
 	var out bytes.Buffer
 
@@ -58,14 +75,21 @@ func (i *Identifier) expressionNode() {}
 func (i *Identifier) TokenLiteral() string {
 	return i.Token.Literal
 }
+func (i *Identifier) Pos() token.Position {
+	return i.Token.Pos
+}
+func (i *Identifier) End() token.Position {
+	return i.Token.End
+}
 func (i *Identifier) String() string {
 	return i.Value
 }
 
 type LetStatement struct {
-	Token token.Token // token.LET
-	Name  *Identifier
-	Value Expression
+	Token   token.Token // token.LET or token.CONST
+	Name    *Identifier
+	Pattern Expression // set instead of Name when the binding destructures
+	Value   Expression
 }
 
 var _ Statement = (*LetStatement)(nil)
@@ -74,11 +98,21 @@ func (s *LetStatement) statementNode() {}
 func (s *LetStatement) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *LetStatement) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *LetStatement) End() token.Position {
+	if s.Value != nil {
+		return s.Value.End()
+	}
+
+	return s.Target().End()
+}
 func (s *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(s.TokenLiteral() + " ")
-	out.WriteString(s.Name.String())
+	out.WriteString(s.Target().String())
 	out.WriteString(" = ")
 
 	if s.Value != nil {
@@ -90,6 +124,16 @@ func (s *LetStatement) String() string {
 	return out.String()
 }
 
+// Target returns what the statement binds: its name, or its pattern when it
+// destructures.
+func (s *LetStatement) Target() Expression {
+	if s.Pattern != nil {
+		return s.Pattern
+	}
+
+	return s.Name
+}
+
 type ReturnStatement struct {
 	Token token.Token // token.RETURN
 	Value Expression
@@ -101,6 +145,16 @@ func (s *ReturnStatement) statementNode() {}
 func (s *ReturnStatement) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *ReturnStatement) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *ReturnStatement) End() token.Position {
+	if s.Value != nil {
+		return s.Value.End()
+	}
+
+	return s.Token.End
+}
 func (s *ReturnStatement) String() string {
 	var out bytes.Buffer
 
@@ -115,6 +169,42 @@ func (s *ReturnStatement) String() string {
 	return out.String()
 }
 
+// ThrowStatement raises Value as an error, as in throw "bad record".
+type ThrowStatement struct {
+	Token token.Token // the 'throw' token
+	Value Expression
+}
+
+var _ Statement = (*ThrowStatement)(nil)
+
+func (s *ThrowStatement) statementNode() {}
+func (s *ThrowStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *ThrowStatement) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *ThrowStatement) End() token.Position {
+	if s.Value != nil {
+		return s.Value.End()
+	}
+
+	return s.Token.End
+}
+func (s *ThrowStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(s.TokenLiteral() + " ")
+
+	if s.Value != nil {
+		out.WriteString(s.Value.String())
+	}
+
+	out.WriteString(";")
+
+	return out.String()
+}
+
 type ExpressionStatement struct {
 	Token      token.Token
 	Expression Expression
@@ -126,6 +216,16 @@ func (s *ExpressionStatement) statementNode() {}
 func (s *ExpressionStatement) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *ExpressionStatement) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *ExpressionStatement) End() token.Position {
+	if s.Expression != nil {
+		return s.Expression.End()
+	}
+
+	return s.Token.End
+}
 func (s *ExpressionStatement) String() string {
 	if s.Expression != nil {
 		return s.Expression.String()
@@ -137,6 +237,7 @@ func (s *ExpressionStatement) String() string {
 type IntegerLiteral struct {
 	Token token.Token
 	Value int64
+	Big   *big.Int // set instead of Value when the literal overflows an int64
 }
 
 var _ Expression = (*IntegerLiteral)(nil)
@@ -145,10 +246,37 @@ func (s *IntegerLiteral) expressionNode() {}
 func (s *IntegerLiteral) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *IntegerLiteral) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *IntegerLiteral) End() token.Position {
+	return s.Token.End
+}
 func (s *IntegerLiteral) String() string {
 	return s.Token.Literal
 }
 
+type FloatLiteral struct {
+	Token token.Token
+	Value float64
+}
+
+var _ Expression = (*FloatLiteral)(nil)
+
+func (s *FloatLiteral) expressionNode() {}
+func (s *FloatLiteral) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *FloatLiteral) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *FloatLiteral) End() token.Position {
+	return s.Token.End
+}
+func (s *FloatLiteral) String() string {
+	return s.Token.Literal
+}
+
 type PrefixExpression struct {
 	Token    token.Token
 	Operator string
@@ -161,6 +289,12 @@ func (s *PrefixExpression) expressionNode() {}
 func (s *PrefixExpression) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *PrefixExpression) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *PrefixExpression) End() token.Position {
+	return s.Right.End()
+}
 func (s *PrefixExpression) String() string {
 	var out bytes.Buffer
 
@@ -185,6 +319,12 @@ func (s *InfixExpression) expressionNode() {}
 func (s *InfixExpression) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *InfixExpression) Pos() token.Position {
+	return s.Left.Pos()
+}
+func (s *InfixExpression) End() token.Position {
+	return s.Right.End()
+}
 func (s *InfixExpression) String() string {
 	var out bytes.Buffer
 
@@ -197,6 +337,139 @@ func (s *InfixExpression) String() string {
 	return out.String()
 }
 
+// LogicalExpression is a short-circuiting &&, || or ?? expression. It is kept
+// apart from InfixExpression as the right operand is evaluated lazily.
+type LogicalExpression struct {
+	Token    token.Token // the &&, || or ?? token
+	Left     Expression
+	Operator string
+	Right    Expression
+}
+
+var _ Expression = (*LogicalExpression)(nil)
+
+func (s *LogicalExpression) expressionNode() {}
+func (s *LogicalExpression) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *LogicalExpression) Pos() token.Position {
+	return s.Left.Pos()
+}
+func (s *LogicalExpression) End() token.Position {
+	return s.Right.End()
+}
+func (s *LogicalExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(s.Left.String())
+	out.WriteString(" " + s.Operator + " ")
+	out.WriteString(s.Right.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// PipeExpression passes Left as the first argument to Right, so x |> f(y) is
+// f(x, y) and x |> f is f(x).
+type PipeExpression struct {
+	Token token.Token // the |> token
+	Left  Expression
+	Right Expression
+}
+
+var _ Expression = (*PipeExpression)(nil)
+
+func (s *PipeExpression) expressionNode() {}
+func (s *PipeExpression) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *PipeExpression) Pos() token.Position {
+	return s.Left.Pos()
+}
+func (s *PipeExpression) End() token.Position {
+	return s.Right.End()
+}
+func (s *PipeExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(s.Left.String())
+	out.WriteString(" |> ")
+	out.WriteString(s.Right.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// ConditionalExpression is the ternary cond ? consequence : alternative,
+// evaluating only the chosen branch.
+type ConditionalExpression struct {
+	Token       token.Token // the '?' token
+	Condition   Expression
+	Consequence Expression
+	Alternative Expression
+}
+
+var _ Expression = (*ConditionalExpression)(nil)
+
+func (s *ConditionalExpression) expressionNode() {}
+func (s *ConditionalExpression) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *ConditionalExpression) Pos() token.Position {
+	return s.Condition.Pos()
+}
+func (s *ConditionalExpression) End() token.Position {
+	return s.Alternative.End()
+}
+func (s *ConditionalExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(s.Condition.String())
+	out.WriteString(" ? ")
+	out.WriteString(s.Consequence.String())
+	out.WriteString(" : ")
+	out.WriteString(s.Alternative.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// AssignExpression assigns to an existing variable or to an element of an
+// array or hash. Operator is "=" or a compound form such as "+=".
+type AssignExpression struct {
+	Token    token.Token // the assignment operator token
+	Target   Expression  // an *Identifier, *IndexExpressopn or *MemberExpression
+	Operator string
+	Value    Expression
+}
+
+var _ Expression = (*AssignExpression)(nil)
+
+func (s *AssignExpression) expressionNode() {}
+func (s *AssignExpression) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *AssignExpression) Pos() token.Position {
+	return s.Target.Pos()
+}
+func (s *AssignExpression) End() token.Position {
+	return s.Value.End()
+}
+func (s *AssignExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(s.Target.String())
+	out.WriteString(" " + s.Operator + " ")
+	out.WriteString(s.Value.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
 type Boolean struct {
 	Token token.Token
 	Value bool
@@ -208,6 +481,12 @@ func (s *Boolean) expressionNode() {}
 func (s *Boolean) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *Boolean) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *Boolean) End() token.Position {
+	return s.Token.End
+}
 func (s *Boolean) String() string {
 	return s.Token.Literal
 }
@@ -215,6 +494,7 @@ func (s *Boolean) String() string {
 type BlockStatement struct {
 	Token      token.Token // the '{' token
 	Statements []Statement
+	Rbrace     token.Token // the '}' token
 }
 
 var _ Statement = (*BlockStatement)(nil)
@@ -223,6 +503,17 @@ func (s *BlockStatement) statementNode() {}
 func (s *BlockStatement) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *BlockStatement) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *BlockStatement) End() token.Position {
+	// the expression body of an arrow function has no braces
+	if s.Rbrace.Type == "" && len(s.Statements) > 0 {
+		return s.Statements[len(s.Statements)-1].End()
+	}
+
+	return s.Rbrace.End
+}
 func (s *BlockStatement) String() string {
 	var out bytes.Buffer
 
@@ -246,6 +537,16 @@ func (s *IfExpression) expressionNode() {}
 func (s *IfExpression) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *IfExpression) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *IfExpression) End() token.Position {
+	if s.Alternative != nil {
+		return s.Alternative.End()
+	}
+
+	return s.Consequence.End()
+}
 func (s *IfExpression) String() string {
 	var out bytes.Buffer
 
@@ -261,9 +562,12 @@ func (s *IfExpression) String() string {
 	return out.String()
 }
 
+// FunctionLiteral is fn(a, b) { ... }, or the arrow shorthand a => ... or
+// (a, b) => ... whose expression body is wrapped in a block.
 type FunctionLiteral struct {
-	Token      token.Token
-	Parameters []*Identifier
+	Token      token.Token // the fn token, or the first token of an arrow function
+	Name       string      // the name it is bound to by a let statement, if any
+	Parameters []*Parameter
 	Body       *BlockStatement
 }
 
@@ -273,6 +577,12 @@ func (s *FunctionLiteral) expressionNode() {}
 func (s *FunctionLiteral) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *FunctionLiteral) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *FunctionLiteral) End() token.Position {
+	return s.Body.End()
+}
 func (s *FunctionLiteral) String() string {
 	var out bytes.Buffer
 
@@ -281,8 +591,7 @@ func (s *FunctionLiteral) String() string {
 		params = append(params, p.String())
 	}
 
-	out.WriteString(s.TokenLiteral())
-	out.WriteString("(")
+	out.WriteString("fn(")
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(")")
 	out.WriteString(s.Body.String())
@@ -290,10 +599,49 @@ func (s *FunctionLiteral) String() string {
 	return out.String()
 }
 
+// Parameter is a parameter of a function literal, as in fn(a, [b, c], d = 1,
+// ...rest).
+type Parameter struct {
+	Token   token.Token // the first token of the parameter
+	Target  Expression  // identifier or destructuring pattern
+	Default Expression  // evaluated at call time when the argument is omitted
+	Rest    bool        // collects the remaining arguments into an array
+}
+
+var _ Node = (*Parameter)(nil)
+
+func (s *Parameter) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *Parameter) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *Parameter) End() token.Position {
+	if s.Default != nil {
+		return s.Default.End()
+	}
+
+	return s.Target.End()
+}
+func (s *Parameter) String() string {
+	if s.Rest {
+		return "..." + s.Target.String()
+	}
+
+	if s.Default != nil {
+		return s.Target.String() + " = " + s.Default.String()
+	}
+
+	return s.Target.String()
+}
+
 type CallExpression struct {
-	Token     token.Token
+	Token     token.Token // the '(' token
 	Function  Expression
 	Arguments []Expression
+	Keywords  []*KeywordArgument // passed by name after the positional arguments
+	Rparen    token.Token        // the ')' token
+	Optional  bool               // written f?.(x), yielding null when f is null
 }
 
 var _ Expression = (*CallExpression)(nil)
@@ -302,6 +650,12 @@ func (s *CallExpression) expressionNode() {}
 func (s *CallExpression) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *CallExpression) Pos() token.Position {
+	return s.Function.Pos()
+}
+func (s *CallExpression) End() token.Position {
+	return s.Rparen.End
+}
 func (s *CallExpression) String() string {
 	var out bytes.Buffer
 
@@ -310,7 +664,16 @@ func (s *CallExpression) String() string {
 		arguments = append(arguments, a.String())
 	}
 
+	for _, k := range s.Keywords {
+		arguments = append(arguments, k.String())
+	}
+
 	out.WriteString(s.Function.String())
+
+	if s.Optional {
+		out.WriteString("?.")
+	}
+
 	out.WriteString("(")
 	out.WriteString(strings.Join(arguments, ", "))
 	out.WriteString(")")
@@ -318,6 +681,29 @@ func (s *CallExpression) String() string {
 	return out.String()
 }
 
+// KeywordArgument passes an argument to a call by parameter name, as in
+// f(x, verbose: true).
+type KeywordArgument struct {
+	Token token.Token // the name token
+	Name  *Identifier
+	Value Expression
+}
+
+var _ Node = (*KeywordArgument)(nil)
+
+func (s *KeywordArgument) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *KeywordArgument) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *KeywordArgument) End() token.Position {
+	return s.Value.End()
+}
+func (s *KeywordArgument) String() string {
+	return s.Name.String() + ": " + s.Value.String()
+}
+
 type StringLiteral struct {
 	Token token.Token
 	Value string
@@ -329,13 +715,43 @@ func (s *StringLiteral) expressionNode() {}
 func (s *StringLiteral) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *StringLiteral) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *StringLiteral) End() token.Position {
+	return s.Token.End
+}
 func (s *StringLiteral) String() string {
 	return s.Token.Literal
 }
 
+// SpreadElement expands an array into the surrounding array literal or
+// argument list, as in [...a, ...b] or f(...args).
+type SpreadElement struct {
+	Token token.Token // the '...' token
+	Value Expression
+}
+
+var _ Expression = (*SpreadElement)(nil)
+
+func (s *SpreadElement) expressionNode() {}
+func (s *SpreadElement) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *SpreadElement) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *SpreadElement) End() token.Position {
+	return s.Value.End()
+}
+func (s *SpreadElement) String() string {
+	return "..." + s.Value.String()
+}
+
 type ArrayLiteral struct {
-	Token    token.Token
+	Token    token.Token // the '[' token
 	Elements []Expression
+	Rbracket token.Token // the ']' token
 }
 
 var _ Expression = (*ArrayLiteral)(nil)
@@ -344,6 +760,12 @@ func (s *ArrayLiteral) expressionNode() {}
 func (s *ArrayLiteral) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *ArrayLiteral) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *ArrayLiteral) End() token.Position {
+	return s.Rbracket.End
+}
 func (s *ArrayLiteral) String() string {
 	var out bytes.Buffer
 
@@ -360,9 +782,11 @@ func (s *ArrayLiteral) String() string {
 }
 
 type IndexExpressopn struct {
-	Token token.Token
-	Left  Expression
-	Index Expression
+	Token    token.Token // the '[' token
+	Left     Expression
+	Index    Expression
+	Rbracket token.Token // the ']' token
+	Optional bool        // written a?.[i], yielding null when a is null
 }
 
 var _ Expression = (*IndexExpressopn)(nil)
@@ -371,11 +795,22 @@ func (s *IndexExpressopn) expressionNode() {}
 func (s *IndexExpressopn) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *IndexExpressopn) Pos() token.Position {
+	return s.Left.Pos()
+}
+func (s *IndexExpressopn) End() token.Position {
+	return s.Rbracket.End
+}
 func (s *IndexExpressopn) String() string {
 	var out bytes.Buffer
 
 	out.WriteString("(")
 	out.WriteString(s.Left.String())
+
+	if s.Optional {
+		out.WriteString("?.")
+	}
+
 	out.WriteString("[")
 	out.WriteString(s.Index.String())
 	out.WriteString("])")
@@ -383,9 +818,99 @@ func (s *IndexExpressopn) String() string {
 	return out.String()
 }
 
+// MemberExpression accesses a named member of a value: a string key of a
+// hash, or a method of a built-in type.
+type MemberExpression struct {
+	Token    token.Token // the '.' token
+	Object   Expression
+	Property *Identifier
+	Optional bool // written a?.name, yielding null when a is null
+}
+
+var _ Expression = (*MemberExpression)(nil)
+
+func (s *MemberExpression) expressionNode() {}
+func (s *MemberExpression) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *MemberExpression) Pos() token.Position {
+	return s.Object.Pos()
+}
+func (s *MemberExpression) End() token.Position {
+	return s.Property.End()
+}
+func (s *MemberExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(s.Object.String())
+
+	if s.Optional {
+		out.WriteString("?.")
+	} else {
+		out.WriteString(".")
+	}
+
+	out.WriteString(s.Property.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// SliceExpression takes the part of an array or string between two indices,
+// as in a[1:3], either of which may be omitted.
+type SliceExpression struct {
+	Token    token.Token // the '[' token
+	Left     Expression
+	Low      Expression  // nil when omitted
+	High     Expression  // nil when omitted
+	Rbracket token.Token // the ']' token
+	Optional bool        // written a?.[i:j], yielding null when a is null
+}
+
+var _ Expression = (*SliceExpression)(nil)
+
+func (s *SliceExpression) expressionNode() {}
+func (s *SliceExpression) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *SliceExpression) Pos() token.Position {
+	return s.Left.Pos()
+}
+func (s *SliceExpression) End() token.Position {
+	return s.Rbracket.End
+}
+func (s *SliceExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(s.Left.String())
+
+	if s.Optional {
+		out.WriteString("?.")
+	}
+
+	out.WriteString("[")
+
+	if s.Low != nil {
+		out.WriteString(s.Low.String())
+	}
+
+	out.WriteString(":")
+
+	if s.High != nil {
+		out.WriteString(s.High.String())
+	}
+
+	out.WriteString("])")
+
+	return out.String()
+}
+
 type HashLiteral struct {
-	Token token.Token
-	Pairs map[Expression]Expression
+	Token  token.Token // the '{' token
+	Pairs  map[Expression]Expression
+	Rbrace token.Token // the '}' token
 }
 
 var _ Expression = (*HashLiteral)(nil)
@@ -394,6 +919,12 @@ func (s *HashLiteral) expressionNode() {}
 func (s *HashLiteral) TokenLiteral() string {
 	return s.Token.Literal
 }
+func (s *HashLiteral) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *HashLiteral) End() token.Position {
+	return s.Rbrace.End
+}
 func (s *HashLiteral) String() string {
 	var out bytes.Buffer
 
@@ -408,3 +939,285 @@ func (s *HashLiteral) String() string {
 
 	return out.String()
 }
+
+// ArrayPattern destructures an array in a binding, as in
+// let [a, [b, c], ...rest] = value.
+type ArrayPattern struct {
+	Token    token.Token  // the '[' token
+	Elements []Expression // identifiers, nested patterns or, in match arms, literals
+	Rest     *Identifier  // bound to the remaining elements, if any
+	Rbracket token.Token  // the ']' token
+}
+
+var _ Expression = (*ArrayPattern)(nil)
+
+func (s *ArrayPattern) expressionNode() {}
+func (s *ArrayPattern) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *ArrayPattern) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *ArrayPattern) End() token.Position {
+	return s.Rbracket.End
+}
+func (s *ArrayPattern) String() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+	for _, element := range s.Elements {
+		elements = append(elements, element.String())
+	}
+
+	if s.Rest != nil {
+		elements = append(elements, "..."+s.Rest.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+// HashPattern destructures a hash by string keys in a binding, as in
+// let {name, age: years} = value.
+type HashPattern struct {
+	Token  token.Token // the '{' token
+	Pairs  []*HashPatternPair
+	Rbrace token.Token // the '}' token
+}
+
+type HashPatternPair struct {
+	Key   string
+	Value Expression // identifier or nested pattern bound to the key's value
+}
+
+var _ Expression = (*HashPattern)(nil)
+
+func (s *HashPattern) expressionNode() {}
+func (s *HashPattern) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *HashPattern) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *HashPattern) End() token.Position {
+	return s.Rbrace.End
+}
+func (s *HashPattern) String() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for _, pair := range s.Pairs {
+		if identifier, ok := pair.Value.(*Identifier); ok && identifier.Value == pair.Key {
+			pairs = append(pairs, pair.Key)
+		} else {
+			pairs = append(pairs, fmt.Sprintf("%s: %s", pair.Key, pair.Value.String()))
+		}
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// MatchExpression evaluates the body of the first arm whose pattern matches
+// the value, as in match (v) { 0 => "zero", [x, ...rest] if x > 0 => x }.
+type MatchExpression struct {
+	Token  token.Token // the 'match' token
+	Value  Expression
+	Arms   []*MatchArm
+	Rbrace token.Token // the '}' token
+}
+
+type MatchArm struct {
+	Pattern Expression // literal, identifier, array or hash pattern; _ matches anything
+	Guard   Expression // checked once the pattern matches, if present
+	Body    Expression
+}
+
+var _ Expression = (*MatchExpression)(nil)
+
+func (s *MatchExpression) expressionNode() {}
+func (s *MatchExpression) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *MatchExpression) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *MatchExpression) End() token.Position {
+	return s.Rbrace.End
+}
+func (s *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	arms := []string{}
+	for _, arm := range s.Arms {
+		if arm.Guard != nil {
+			arms = append(arms, fmt.Sprintf("%s if %s => %s", arm.Pattern.String(), arm.Guard.String(), arm.Body.String()))
+		} else {
+			arms = append(arms, fmt.Sprintf("%s => %s", arm.Pattern.String(), arm.Body.String()))
+		}
+	}
+
+	out.WriteString("match (")
+	out.WriteString(s.Value.String())
+	out.WriteString(") { ")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// TryExpression is try { ... } catch (e) { ... } finally { ... }, where at
+// least one of the catch and finally blocks is present.
+type TryExpression struct {
+	Token     token.Token // the 'try' token
+	Block     *BlockStatement
+	Parameter Expression // identifier or pattern bound to the caught error
+	Catch     *BlockStatement
+	Finally   *BlockStatement
+}
+
+var _ Expression = (*TryExpression)(nil)
+
+func (s *TryExpression) expressionNode() {}
+func (s *TryExpression) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *TryExpression) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *TryExpression) End() token.Position {
+	if s.Finally != nil {
+		return s.Finally.End()
+	}
+
+	return s.Catch.End()
+}
+func (s *TryExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(s.Block.String())
+
+	if s.Catch != nil {
+		out.WriteString(" catch (")
+		out.WriteString(s.Parameter.String())
+		out.WriteString(") ")
+		out.WriteString(s.Catch.String())
+	}
+
+	if s.Finally != nil {
+		out.WriteString(" finally ")
+		out.WriteString(s.Finally.String())
+	}
+
+	return out.String()
+}
+
+type WhileStatement struct {
+	Token     token.Token // the 'while' token
+	Condition Expression
+	Body      *BlockStatement
+}
+
+var _ Statement = (*WhileStatement)(nil)
+
+func (s *WhileStatement) statementNode() {}
+func (s *WhileStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *WhileStatement) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *WhileStatement) End() token.Position {
+	return s.Body.End()
+}
+func (s *WhileStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("while (")
+	out.WriteString(s.Condition.String())
+	out.WriteString(") ")
+	out.WriteString(s.Body.String())
+
+	return out.String()
+}
+
+// ForStatement iterates over the elements of an array, the characters of a
+// string or the keys of a hash, binding each in turn to Variable.
+type ForStatement struct {
+	Token    token.Token // the 'for' token
+	Variable *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+var _ Statement = (*ForStatement)(nil)
+
+func (s *ForStatement) statementNode() {}
+func (s *ForStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *ForStatement) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *ForStatement) End() token.Position {
+	return s.Body.End()
+}
+func (s *ForStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for (")
+	out.WriteString(s.Variable.String())
+	out.WriteString(" in ")
+	out.WriteString(s.Iterable.String())
+	out.WriteString(") ")
+	out.WriteString(s.Body.String())
+
+	return out.String()
+}
+
+type BreakStatement struct {
+	Token token.Token // the 'break' token
+}
+
+var _ Statement = (*BreakStatement)(nil)
+
+func (s *BreakStatement) statementNode() {}
+func (s *BreakStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *BreakStatement) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *BreakStatement) End() token.Position {
+	return s.Token.End
+}
+func (s *BreakStatement) String() string {
+	return s.TokenLiteral() + ";"
+}
+
+type ContinueStatement struct {
+	Token token.Token // the 'continue' token
+}
+
+var _ Statement = (*ContinueStatement)(nil)
+
+func (s *ContinueStatement) statementNode() {}
+func (s *ContinueStatement) TokenLiteral() string {
+	return s.Token.Literal
+}
+func (s *ContinueStatement) Pos() token.Position {
+	return s.Token.Pos
+}
+func (s *ContinueStatement) End() token.Position {
+	return s.Token.End
+}
+func (s *ContinueStatement) String() string {
+	return s.TokenLiteral() + ";"
+}
@@ -2,27 +2,59 @@ package evaluator
 
 import (
 	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/Jamess-Lucass/interpreter-go/ast"
 	"github.com/Jamess-Lucass/interpreter-go/object"
+	"github.com/Jamess-Lucass/interpreter-go/token"
+)
+
+// maxCallDepth bounds nested function calls so that runaway recursion is
+// reported as an error rather than exhausting the Go stack, which cannot be
+// recovered from. A call whose body nests loops, try, match and pipes takes
+// around 20KB of Go stack and overflows the 1GB limit at about 45,000 calls,
+// so the bound leaves several times that headroom for heavier bodies.
+const maxCallDepth = 10000
+
+// maxIntegerBits bounds the size of integers produced by shifts and powers,
+// so that a huge shift count or exponent is reported as an error rather than
+// exhausting memory or running for ever.
+const maxIntegerBits = 1 << 20
+
+// maxStackFrames bounds the functions recorded in the stack of an error, so
+// that an error out of deep recursion does not carry an enormous trace.
+const maxStackFrames = 100
+
+// Error kinds seen by catch blocks: errors raised by the interpreter and its
+// builtins, and values thrown without a kind of their own.
+const (
+	runtimeErrorKind = "RuntimeError"
+	thrownErrorKind  = "Error"
 )
 
 var (
 	TRUE  = &object.Boolean{Value: true}
 	FALSE = &object.Boolean{Value: false}
 	NULL  = &object.Null{}
+
+	BREAK    = &object.Break{}
+	CONTINUE = &object.Continue{}
 )
 
 var builtins = map[string]*object.Builtin{
 	"len": {
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(_ object.Keywords, args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return newError("wrong number of arguments. got=%d, want=1", len(args))
 			}
 
 			switch arg := args[0].(type) {
 			case *object.String:
-				return &object.Integer{Value: int64(len(arg.Value))}
+				return &object.Integer{Value: int64(utf8.RuneCountInString(arg.Value))}
 			case *object.Array:
 				return &object.Integer{Value: int64(len(arg.Elements))}
 			default:
@@ -31,7 +63,7 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"first": {
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(_ object.Keywords, args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return newError("wrong number of arguments. got=%d, want=1", len(args))
 			}
@@ -49,7 +81,7 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"last": {
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(_ object.Keywords, args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return newError("wrong number of arguments. got=%d, want=1", len(args))
 			}
@@ -68,7 +100,7 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"rest": {
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(_ object.Keywords, args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return newError("wrong number of arguments. got=%d, want=1", len(args))
 			}
@@ -90,7 +122,7 @@ var builtins = map[string]*object.Builtin{
 		},
 	},
 	"push": {
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(_ object.Keywords, args ...object.Object) object.Object {
 			if len(args) != 2 {
 				return newError("wrong number of arguments. got=%d, want=2", len(args))
 			}
@@ -110,8 +142,19 @@ var builtins = map[string]*object.Builtin{
 			return &object.Array{Elements: newElements}
 		},
 	},
+	"freeze": {
+		Fn: func(_ object.Keywords, args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return newError("wrong number of arguments. got=%d, want=1", len(args))
+			}
+
+			freeze(args[0])
+
+			return args[0]
+		},
+	},
 	"puts": {
-		Fn: func(args ...object.Object) object.Object {
+		Fn: func(_ object.Keywords, args ...object.Object) object.Object {
 			for _, arg := range args {
 				fmt.Println(arg.Inspect())
 			}
@@ -121,31 +164,81 @@ var builtins = map[string]*object.Builtin{
 	},
 }
 
+// freeze marks obj and every array and hash reachable from it as immutable.
+// Other values are already immutable and are left untouched.
+func freeze(obj object.Object) {
+	switch obj := obj.(type) {
+	case *object.Array:
+		if obj.Frozen {
+			return
+		}
+
+		obj.Frozen = true
+		for _, element := range obj.Elements {
+			freeze(element)
+		}
+	case *object.Hash:
+		if obj.Frozen {
+			return
+		}
+
+		obj.Frozen = true
+		for _, pair := range obj.Pairs {
+			freeze(pair.Value)
+		}
+	}
+}
+
 func newError(format string, a ...interface{}) *object.Error {
-	return &object.Error{Message: fmt.Sprintf(format, a...)}
+	return &object.Error{Message: fmt.Sprintf(format, a...), Kind: runtimeErrorKind}
 }
 
-func isError(obj object.Object) bool {
-	if obj != nil {
-		return obj.Type() == object.ERROR_OBJ
+// isUnwinding reports whether obj is unwinding evaluation rather than a value:
+// an error, or a return, break or continue on its way to the function or loop
+// it belongs to. Such objects are handed straight up instead of being used.
+func isUnwinding(obj object.Object) bool {
+	if obj == nil {
+		return false
 	}
 
-	return false
+	switch obj.Type() {
+	case object.RETURN_VALUE_OBJ, object.ERROR_OBJ, object.BREAK_OBJ, object.CONTINUE_OBJ:
+		return true
+	default:
+		return false
+	}
 }
 
 func Eval(node ast.Node, env *object.Environment) object.Object {
+	result := evalNode(node, env)
+
+	// Errors are positioned at the innermost node that produced them.
+	if err, ok := result.(*object.Error); ok && !err.Pos.IsValid() {
+		err.Pos = node.Pos()
+	}
+
+	return result
+}
+
+func evalNode(node ast.Node, env *object.Environment) object.Object {
 	switch node := node.(type) {
 	case *ast.Program:
 		return evalProgram(node, env)
 	case *ast.ExpressionStatement:
 		return Eval(node.Expression, env)
 	case *ast.IntegerLiteral:
+		if node.Big != nil {
+			return object.NewBigInteger(new(big.Int).Set(node.Big))
+		}
+
 		return &object.Integer{Value: node.Value}
+	case *ast.FloatLiteral:
+		return &object.Float{Value: node.Value}
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
 	case *ast.ArrayLiteral:
 		elements := evalExpressions(node.Elements, env)
-		if len(elements) == 1 && isError(elements[0]) {
+		if len(elements) == 1 && isUnwinding(elements[0]) {
 			return elements[0]
 		}
 		return &object.Array{Elements: elements}
@@ -156,71 +249,110 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return FALSE
 	case *ast.PrefixExpression:
 		right := Eval(node.Right, env)
-		if isError(right) {
+		if isUnwinding(right) {
 			return right
 		}
 
 		return evalPrefixExpression(node.Operator, right)
 	case *ast.InfixExpression:
 		left := Eval(node.Left, env)
-		if isError(left) {
+		if isUnwinding(left) {
 			return left
 		}
 
 		right := Eval(node.Right, env)
-		if isError(right) {
+		if isUnwinding(right) {
 			return right
 		}
 
 		return evalInfixExpression(left, node.Operator, right)
+	case *ast.LogicalExpression:
+		return evalLogicalExpression(node, env)
+	case *ast.AssignExpression:
+		return evalAssignExpression(node, env)
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
+	case *ast.MemberExpression:
+		result, _ := evalMemberExpression(node, env)
+		return result
+	case *ast.SliceExpression:
+		result, _ := evalSliceExpression(node, env)
+		return result
+	case *ast.ConditionalExpression:
+		condition := Eval(node.Condition, env)
+		if isUnwinding(condition) {
+			return condition
+		}
+
+		if isTruthy(condition) {
+			return Eval(node.Consequence, env)
+		}
+
+		return Eval(node.Alternative, env)
 	case *ast.BlockStatement:
 		return evalBlockStatement(node, env)
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
 	case *ast.ReturnStatement:
 		val := Eval(node.Value, env)
-		if isError(val) {
+		if isUnwinding(val) {
 			return val
 		}
 
 		return &object.ReturnValue{Value: val}
+	case *ast.ThrowStatement:
+		val := Eval(node.Value, env)
+		if isUnwinding(val) {
+			return val
+		}
+
+		return newThrownError(val)
+	case *ast.TryExpression:
+		return evalTryExpression(node, env)
+	case *ast.WhileStatement:
+		return evalWhileStatement(node, env)
+	case *ast.ForStatement:
+		return evalForStatement(node, env)
+	case *ast.BreakStatement:
+		return BREAK
+	case *ast.ContinueStatement:
+		return CONTINUE
 	case *ast.LetStatement:
 		val := Eval(node.Value, env)
-		if isError(val) {
+		if isUnwinding(val) {
 			return val
 		}
 
-		env.Set(node.Name.Value, val)
+		err := destructure(node.Target(), val, func(name *ast.Identifier, value object.Object) *object.Error {
+			if env.IsLocalConstant(name.Value) {
+				return newError("cannot redeclare constant: %s", name.Value)
+			}
+
+			if node.Token.Type == token.CONST {
+				env.SetConstant(name.Value, value)
+			} else {
+				env.Set(name.Value, value)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
 	case *ast.Identifier:
 		return evalIdentifier(node, env)
 	case *ast.FunctionLiteral:
 		params := node.Parameters
 		body := node.Body
-		return &object.Function{Parameters: params, Body: body, Env: env}
+		return &object.Function{Name: node.Name, Parameters: params, Body: body, Env: env}
 	case *ast.CallExpression:
-		function := Eval(node.Function, env)
-		if isError(function) {
-			return function
-		}
-
-		args := evalExpressions(node.Arguments, env)
-		if len(args) == 1 && isError(args[0]) {
-			return args[0]
-		}
-
-		return applyFunction(function, args)
+		result, _ := evalCallExpression(node, env, nil)
+		return result
+	case *ast.PipeExpression:
+		return evalPipeExpression(node, env)
 	case *ast.IndexExpressopn:
-		left := Eval(node.Left, env)
-		if isError(left) {
-			return left
-		}
-
-		index := Eval(node.Index, env)
-		if isError(index) {
-			return index
-		}
-
-		return evalIndexExpression(left, index)
+		result, _ := evalIndexAccess(node, env)
+		return result
 	case *ast.HashLiteral:
 		return evalHashLiteral(node, env)
 	}
@@ -228,258 +360,1409 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	return nil
 }
 
-func applyFunction(fn object.Object, args []object.Object) object.Object {
-	switch fn := fn.(type) {
-	case *object.Function:
-		extendedEnv := extendFunctionEnv(fn, args)
-		evaluated := Eval(fn.Body, extendedEnv)
-
-		if returnValue, ok := evaluated.(*object.ReturnValue); ok {
-			return returnValue.Value
-		}
+// evalChainOperand evaluates the operand of an index, slice, member or call
+// expression, reporting whether the rest of the chain is skipped. It is once
+// a ?. link finds NULL, and stays so for the links after it in the same
+// chain, so that a?.b.c[0] is NULL rather than an error when a is NULL.
+func evalChainOperand(operand ast.Expression, optional bool, env *object.Environment) (object.Object, bool) {
+	var left object.Object
+	skipped := false
 
-		return evaluated
-	case *object.Builtin:
-		return fn.Fn(args...)
+	switch operand := operand.(type) {
+	case *ast.IndexExpressopn:
+		left, skipped = evalIndexAccess(operand, env)
+	case *ast.SliceExpression:
+		left, skipped = evalSliceExpression(operand, env)
+	case *ast.MemberExpression:
+		left, skipped = evalMemberExpression(operand, env)
+	case *ast.CallExpression:
+		left, skipped = evalCallExpression(operand, env, nil)
 	default:
-		return newError("not a function: %s", fn.Type())
+		left = Eval(operand, env)
+	}
+
+	if err, ok := left.(*object.Error); ok && !err.Pos.IsValid() {
+		err.Pos = operand.Pos()
 	}
+
+	return left, skipped || optional && left == NULL
 }
 
-func extendFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
-	env := object.NewEnclosedEnvironment(fn.Env)
+// evalIndexAccess evaluates left[index], reporting like evalChainOperand
+// whether an optional chain it belongs to was skipped.
+func evalIndexAccess(node *ast.IndexExpressopn, env *object.Environment) (object.Object, bool) {
+	left, skipped := evalChainOperand(node.Left, node.Optional, env)
+	if skipped || isUnwinding(left) {
+		return left, skipped
+	}
 
-	for index, param := range fn.Parameters {
-		env.Set(param.Value, args[index])
+	index := Eval(node.Index, env)
+	if isUnwinding(index) {
+		return index, false
 	}
 
-	return env
+	return evalIndexExpression(left, index), false
 }
 
-func evalProgram(program *ast.Program, env *object.Environment) object.Object {
-	var result object.Object
-
-	for _, statement := range program.Statements {
-		result = Eval(statement, env)
+// evalCallExpression evaluates a call. A value piped in with |> is passed as
+// the first argument, ahead of the call's own arguments.
+func evalCallExpression(node *ast.CallExpression, env *object.Environment, piped object.Object) (object.Object, bool) {
+	function, skipped := evalChainOperand(node.Function, node.Optional, env)
+	if skipped || isUnwinding(function) {
+		return function, skipped
+	}
 
-		switch result := result.(type) {
-		case *object.ReturnValue:
-			return result.Value
-		case *object.Error:
-			return result
-		}
+	if piped != nil && !isCallable(function) {
+		return newPipeTargetError(node.Function, function), false
 	}
 
-	return result
-}
+	args := evalExpressions(node.Arguments, env)
+	if len(args) == 1 && isUnwinding(args[0]) {
+		return args[0], false
+	}
 
-func evalBlockStatement(statement *ast.BlockStatement, env *object.Environment) object.Object {
-	var result object.Object
+	if piped != nil {
+		args = append([]object.Object{piped}, args...)
+	}
 
-	for _, statement := range statement.Statements {
-		result = Eval(statement, env)
+	var kwargs object.Keywords
+	if len(node.Keywords) > 0 {
+		kwargs = make(object.Keywords, len(node.Keywords))
+	}
 
-		if result != nil {
-			rt := result.Type()
-			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
-				return result
-			}
+	for _, keyword := range node.Keywords {
+		value := Eval(keyword.Value, env)
+		if isUnwinding(value) {
+			return value, false
 		}
+
+		kwargs[keyword.Name.Value] = value
 	}
 
-	return result
+	return applyFunction(function, args, kwargs, env), false
 }
 
-func evalPrefixExpression(operator string, right object.Object) object.Object {
-	switch operator {
-	case "!":
-		return evalBangOperatorExpression(right)
-	case "-":
-		return evalMinusPrefixOperatorExpression(right)
-	default:
-		return newError("unknown operator: %s%s", operator, right.Type())
+// evalPipeExpression evaluates x |> f(y) as f(x, y), and x |> f as f(x).
+func evalPipeExpression(node *ast.PipeExpression, env *object.Environment) object.Object {
+	left := Eval(node.Left, env)
+	if isUnwinding(left) {
+		return left
 	}
-}
 
-func evalBangOperatorExpression(right object.Object) object.Object {
-	switch right {
-	case TRUE:
-		return FALSE
-	case FALSE:
-		return TRUE
-	case NULL:
-		return TRUE
-	default:
-		return FALSE
+	if call, ok := node.Right.(*ast.CallExpression); ok {
+		result, _ := evalCallExpression(call, env, left)
+		return result
 	}
-}
 
-func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
-	if right.Type() != object.INTEGER_OBJ {
-		return newError("unknown operator: -%s", right.Type())
+	function := Eval(node.Right, env)
+	if isUnwinding(function) {
+		return function
 	}
 
-	value := right.(*object.Integer).Value
+	if !isCallable(function) {
+		return newPipeTargetError(node.Right, function)
+	}
 
-	return &object.Integer{Value: -value}
+	return applyFunction(function, []object.Object{left}, nil, env)
 }
 
-func evalInfixExpression(left object.Object, operator string, right object.Object) object.Object {
-	switch {
-	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
-		return evalIntegerInfixExpression(left, operator, right)
-	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
-		return evalStringInfixExpression(left, operator, right)
-	case operator == "==":
-		return nativeBoolToBooleanObject(left == right)
-	case operator == "!=":
-		return nativeBoolToBooleanObject(left != right)
-	case left.Type() != right.Type():
-		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+func newPipeTargetError(target ast.Expression, function object.Object) *object.Error {
+	err := newError("cannot pipe into %s: not a function", function.Type())
+	err.Pos = target.Pos()
+
+	return err
+}
+
+func isCallable(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Function, *object.Builtin:
+		return true
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return false
 	}
 }
 
-func evalIntegerInfixExpression(left object.Object, operator string, right object.Object) object.Object {
-	leftValue := left.(*object.Integer).Value
-	rightValue := right.(*object.Integer).Value
+func applyFunction(fn object.Object, args []object.Object, kwargs object.Keywords, caller *object.Environment) object.Object {
+	switch fn := fn.(type) {
+	case *object.Function:
+		if caller.Depth() >= maxCallDepth {
+			return newError("maximum call depth of %d exceeded", maxCallDepth)
+		}
 
-	switch operator {
-	case "+":
-		return &object.Integer{Value: leftValue + rightValue}
-	case "-":
-		return &object.Integer{Value: leftValue - rightValue}
-	case "*":
-		return &object.Integer{Value: leftValue * rightValue}
-	case "/":
-		return &object.Integer{Value: leftValue / rightValue}
-	case "<":
-		return nativeBoolToBooleanObject(leftValue < rightValue)
-	case ">":
-		return nativeBoolToBooleanObject(leftValue > rightValue)
-	case "==":
-		return nativeBoolToBooleanObject(leftValue == rightValue)
-	case "!=":
-		return nativeBoolToBooleanObject(leftValue != rightValue)
+		if err := checkKeywords(fn, args, kwargs); err != nil {
+			return err
+		}
+
+		// every keyword now fills a parameter not given by position
+		if err := checkArity(fn, len(args)+len(kwargs)); err != nil {
+			return err
+		}
+
+		extendedEnv, err := extendFunctionEnv(fn, args, kwargs, caller)
+		if err != nil {
+			return err
+		}
+
+		evaluated := evalBlockStatement(fn.Body, extendedEnv)
+
+		if err, ok := evaluated.(*object.Error); ok && len(err.Stack) < maxStackFrames {
+			err.Stack = append(err.Stack, frameName(fn))
+		}
+
+		if returnValue, ok := evaluated.(*object.ReturnValue); ok {
+			return returnValue.Value
+		}
+
+		return evaluated
+	case *object.Builtin:
+		if name, ok := unknownKeyword(kwargs, fn.Keywords); ok {
+			return newError("unknown keyword argument: %s", name)
+		}
+
+		return fn.Fn(kwargs, args...)
 	default:
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+		return newError("not a function: %s", fn.Type())
 	}
 }
 
-func evalStringInfixExpression(left object.Object, operator string, right object.Object) object.Object {
-	if operator != "+" {
-		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+// functionName describes fn for use in error messages.
+func functionName(fn *object.Function) string {
+	if fn.Name == "" {
+		return "anonymous function"
 	}
 
-	leftValue := left.(*object.String).Value
-	rightValue := right.(*object.String).Value
-
-	return &object.String{Value: leftValue + rightValue}
+	return fmt.Sprintf("`%s`", fn.Name)
 }
 
-func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Object {
-	condition := Eval(ie.Condition, env)
-	if isError(condition) {
-		return condition
+// frameName describes fn in the stack of an error.
+func frameName(fn *object.Function) string {
+	if fn.Name == "" {
+		return "anonymous function"
 	}
 
-	if isTruthy(condition) {
-		return Eval(ie.Consequence, env)
-	} else if ie.Alternative != nil {
-		return Eval(ie.Alternative, env)
+	return fn.Name
+}
+
+// checkKeywords reports keyword arguments that name no parameter of fn, or
+// that name a parameter already given by position.
+func checkKeywords(fn *object.Function, args []object.Object, kwargs object.Keywords) *object.Error {
+	if len(kwargs) == 0 {
+		return nil
 	}
 
-	return NULL
-}
+	names := []string{}
+	for _, param := range fn.Parameters {
+		if name := keywordName(param); name != "" {
+			names = append(names, name)
+		}
+	}
 
-func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object {
-	if val, ok := env.Get(node.Value); ok {
-		return val
+	if name, ok := unknownKeyword(kwargs, names); ok {
+		return newError("unknown keyword argument to %s: %s", functionName(fn), name)
 	}
 
-	if builtin, ok := builtins[node.Value]; ok {
-		return builtin
+	for index, param := range fn.Parameters {
+		if _, named := kwargs[keywordName(param)]; named && index < len(args) {
+			return newError("multiple values for argument %s to %s", keywordName(param), functionName(fn))
+		}
 	}
 
-	return newError(fmt.Sprintf("identifier not found: %s", node.Value))
+	return nil
 }
 
-func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Object {
-	var result []object.Object
+// checkArity reports an error unless fn accepts count arguments, taking
+// defaulted and rest parameters into account.
+func checkArity(fn *object.Function, count int) *object.Error {
+	required, variadic := 0, false
 
-	for _, e := range exps {
-		evaluated := Eval(e, env)
-		if isError(evaluated) {
-			return []object.Object{evaluated}
+	for _, param := range fn.Parameters {
+		if param.Rest {
+			variadic = true
+		} else if param.Default == nil {
+			required++
 		}
-		result = append(result, evaluated)
 	}
 
-	return result
-}
-
-func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
-	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
-		return evalArrayIndexExpression(left, index)
-	case left.Type() == object.HASH_OBJ:
-		return evalHashIndexExpression(left, index)
-	default:
-		return newError("index operator not supported: %s", left.Type())
+	case variadic && count < required:
+		return newError("wrong number of arguments to %s: want>=%d, got=%d", functionName(fn), required, count)
+	case variadic:
+		return nil
+	case required == len(fn.Parameters) && count != required:
+		return newError("wrong number of arguments to %s: want=%d, got=%d", functionName(fn), required, count)
+	case count < required || count > len(fn.Parameters):
+		return newError("wrong number of arguments to %s: want=%d..%d, got=%d", functionName(fn), required, len(fn.Parameters), count)
 	}
-}
 
-func evalArrayIndexExpression(array, index object.Object) object.Object {
-	arrayObj := array.(*object.Array)
-	idx := index.(*object.Integer).Value
+	return nil
+}
 
-	max := int64(len(arrayObj.Elements) - 1)
+// unknownKeyword returns the first of the keyword arguments, in name order,
+// that is not one of the accepted names.
+func unknownKeyword(kwargs object.Keywords, accepted []string) (string, bool) {
+	names := make([]string, 0, len(kwargs))
+	for name := range kwargs {
+		if !slices.Contains(accepted, name) {
+			names = append(names, name)
+		}
+	}
 
-	if idx < 0 || idx > max {
-		return NULL
+	if len(names) == 0 {
+		return "", false
 	}
 
-	return arrayObj.Elements[idx]
+	return slices.Min(names), true
 }
 
-func evalHashIndexExpression(array, index object.Object) object.Object {
-	hashObj := array.(*object.Hash)
-
-	key, ok := index.(object.Hashable)
-	if !ok {
-		return newError("unusable as hash key: %s", index.Type())
-	}
-
-	pair, ok := hashObj.Pairs[key.HashKey()]
-	if !ok {
-		return NULL
+// keywordName returns the name by which param can be passed as a keyword
+// argument. Rest and destructuring parameters have no such name.
+func keywordName(param *ast.Parameter) string {
+	if identifier, ok := param.Target.(*ast.Identifier); ok && !param.Rest {
+		return identifier.Value
 	}
 
-	return pair.Value
+	return ""
 }
 
-func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair)
+func extendFunctionEnv(fn *object.Function, args []object.Object, kwargs object.Keywords, caller *object.Environment) (*object.Environment, *object.Error) {
+	env := object.NewCallEnvironment(fn.Env, caller)
 
-	for keyNode, valueNode := range node.Pairs {
-		key := Eval(keyNode, env)
-		if isError(key) {
-			return key
-		}
+	for index, param := range fn.Parameters {
+		var value object.Object
 
-		hashKey, ok := key.(object.Hashable)
-		if !ok {
-			return newError("unusable as hash key: %s", key.Type())
-		}
+		keyword, named := kwargs[keywordName(param)]
 
-		value := Eval(valueNode, env)
-		if isError(value) {
-			return value
+		switch {
+		case param.Rest:
+			rest := []object.Object{}
+			if index < len(args) {
+				rest = append(rest, args[index:]...)
+			}
+
+			value = &object.Array{Elements: rest}
+		case index < len(args):
+			value = args[index]
+		case named:
+			value = keyword
+		case param.Default == nil:
+			return nil, newError("missing argument %s to %s", param.Target.String(), functionName(fn))
+		default:
+			// defaults see the closure and the parameters bound before them
+			value = Eval(param.Default, env)
+			if err, ok := value.(*object.Error); ok {
+				return nil, err
+			}
 		}
 
-		hashed := hashKey.HashKey()
-		pairs[hashed] = object.HashPair{Key: key, Value: value}
+		err := destructure(param.Target, value, func(name *ast.Identifier, value object.Object) *object.Error {
+			env.Set(name.Value, value)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return env, nil
+}
+
+// destructure matches value against the binding pattern, calling bind for
+// every identifier in the pattern with the part of value it names. An error
+// is returned, positioned at the offending part of the pattern, when the shape
+// of value does not fit the pattern or bind fails.
+func destructure(pattern ast.Expression, value object.Object, bind func(name *ast.Identifier, value object.Object) *object.Error) *object.Error {
+	var err *object.Error
+
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		err = bind(pattern, value)
+	case *ast.ArrayPattern:
+		err = destructureArray(pattern, value, bind)
+	case *ast.HashPattern:
+		err = destructureHash(pattern, value, bind)
+	case *ast.IntegerLiteral, *ast.FloatLiteral, *ast.StringLiteral, *ast.Boolean, *ast.PrefixExpression:
+		// literal patterns, only parsed in match arms, never refer to the
+		// environment
+		if evalInfixExpression(value, "==", Eval(pattern, nil)) != TRUE {
+			err = newError("%s does not match %s", value.Inspect(), pattern.String())
+		}
+	default:
+		err = newError("invalid binding pattern: %s", pattern.String())
+	}
+
+	if err != nil && !err.Pos.IsValid() {
+		err.Pos = pattern.Pos()
+	}
+
+	return err
+}
+
+func destructureArray(pattern *ast.ArrayPattern, value object.Object, bind func(name *ast.Identifier, value object.Object) *object.Error) *object.Error {
+	array, ok := value.(*object.Array)
+	if !ok {
+		return newError("cannot destructure %s as array", value.Type())
+	}
+
+	if pattern.Rest == nil && len(array.Elements) != len(pattern.Elements) {
+		return newError("array pattern expects %d elements, got %d", len(pattern.Elements), len(array.Elements))
+	}
+
+	if len(array.Elements) < len(pattern.Elements) {
+		return newError("array pattern expects at least %d elements, got %d", len(pattern.Elements), len(array.Elements))
+	}
+
+	for index, element := range pattern.Elements {
+		if err := destructure(element, array.Elements[index], bind); err != nil {
+			return err
+		}
+	}
+
+	if pattern.Rest != nil {
+		rest := make([]object.Object, len(array.Elements)-len(pattern.Elements))
+		copy(rest, array.Elements[len(pattern.Elements):])
+
+		return destructure(pattern.Rest, &object.Array{Elements: rest}, bind)
+	}
+
+	return nil
+}
+
+func destructureHash(pattern *ast.HashPattern, value object.Object, bind func(name *ast.Identifier, value object.Object) *object.Error) *object.Error {
+	hash, ok := value.(*object.Hash)
+	if !ok {
+		return newError("cannot destructure %s as hash", value.Type())
+	}
+
+	for _, pair := range pattern.Pairs {
+		key := &object.String{Value: pair.Key}
+
+		found, ok := hash.Pairs[key.HashKey()]
+		if !ok {
+			err := newError("hash has no key %q", pair.Key)
+			err.Pos = pair.Value.Pos()
+
+			return err
+		}
+
+		if err := destructure(pair.Value, found.Value, bind); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func evalProgram(program *ast.Program, env *object.Environment) (result object.Object) {
+	// A bug in the evaluator or a builtin must not bring down the host, so
+	// any panic is reported as an error from the program instead.
+	defer func() {
+		if r := recover(); r != nil {
+			result = newError("internal error: %v", r)
+		}
+	}()
+
+	for _, statement := range program.Statements {
+		result = Eval(statement, env)
+
+		switch result := result.(type) {
+		case *object.ReturnValue:
+			return result.Value
+		case *object.Error:
+			return result
+		}
+	}
+
+	return result
+}
+
+func evalBlockStatement(statement *ast.BlockStatement, env *object.Environment) object.Object {
+	var result object.Object
+
+	for _, statement := range statement.Statements {
+		// An expression statement is positioned where its expression is, so
+		// evaluating the expression directly loses nothing and keeps the Go
+		// stack used by each level of recursion down.
+		if expression, ok := statement.(*ast.ExpressionStatement); ok && expression.Expression != nil {
+			result = Eval(expression.Expression, env)
+		} else {
+			result = Eval(statement, env)
+		}
+
+		if isUnwinding(result) {
+			return result
+		}
+	}
+
+	return result
+}
+
+func evalWhileStatement(node *ast.WhileStatement, env *object.Environment) object.Object {
+	for {
+		condition := Eval(node.Condition, env)
+		if isUnwinding(condition) {
+			return condition
+		}
+
+		if !isTruthy(condition) {
+			return NULL
+		}
+
+		result := Eval(node.Body, object.NewEnclosedEnvironment(env))
+		if result, done := loopControl(result); done {
+			return result
+		}
+	}
+}
+
+func evalForStatement(node *ast.ForStatement, env *object.Environment) object.Object {
+	iterable := Eval(node.Iterable, env)
+	if isUnwinding(iterable) {
+		return iterable
+	}
+
+	var result object.Object = NULL
+
+	err := forEachElement(iterable, func(element object.Object) bool {
+		iterationEnv := object.NewEnclosedEnvironment(env)
+		iterationEnv.Set(node.Variable.Value, element)
+
+		var done bool
+		result, done = loopControl(Eval(node.Body, iterationEnv))
+
+		return !done
+	})
+	if err != nil {
+		return err
+	}
+
+	return result
+}
+
+// loopControl interprets the result of a loop body, reporting whether the
+// loop is done and, if so, the result of the loop.
+func loopControl(result object.Object) (object.Object, bool) {
+	if result == nil {
+		return NULL, false
+	}
+
+	switch result.Type() {
+	case object.BREAK_OBJ:
+		return NULL, true
+	case object.RETURN_VALUE_OBJ, object.ERROR_OBJ:
+		return result, true
+	default:
+		return NULL, false
+	}
+}
+
+// forEachElement calls fn with each element of an array, each character of a
+// string or each key of a hash, in sorted order, until fn returns false.
+func forEachElement(iterable object.Object, fn func(object.Object) bool) *object.Error {
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		for i := 0; i < len(iterable.Elements); i++ {
+			if !fn(iterable.Elements[i]) {
+				return nil
+			}
+		}
+	case *object.String:
+		for _, character := range iterable.Value {
+			if !fn(&object.String{Value: string(character)}) {
+				return nil
+			}
+		}
+	case *object.Hash:
+		for _, pair := range iterable.SortedPairs() {
+			if !fn(pair.Key) {
+				return nil
+			}
+		}
+	case *object.Range:
+		// stepping past End could overflow, so stop on reaching it
+		for i := iterable.Start; i < iterable.End || (iterable.Inclusive && i == iterable.End); i++ {
+			if !fn(&object.Integer{Value: i}) || i == iterable.End {
+				return nil
+			}
+		}
+	default:
+		return newError("cannot iterate over %s", iterable.Type())
+	}
+
+	return nil
+}
+
+func evalPrefixExpression(operator string, right object.Object) object.Object {
+	switch operator {
+	case "!":
+		return evalBangOperatorExpression(right)
+	case "-":
+		return evalMinusPrefixOperatorExpression(right)
+	case "~":
+		return evalBitwiseNotPrefixOperatorExpression(right)
+	default:
+		return newError("unknown operator: %s%s", operator, right.Type())
+	}
+}
+
+func evalBangOperatorExpression(right object.Object) object.Object {
+	switch right {
+	case TRUE:
+		return FALSE
+	case FALSE:
+		return TRUE
+	case NULL:
+		return TRUE
+	default:
+		return FALSE
+	}
+}
+
+func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Integer:
+		if right.IsBig() || right.Value == math.MinInt64 {
+			return object.NewBigInteger(new(big.Int).Neg(right.BigValue()))
+		}
+
+		return &object.Integer{Value: -right.Value}
+	case *object.Float:
+		return &object.Float{Value: -right.Value}
+	default:
+		return newError("unknown operator: -%s", right.Type())
+	}
+}
+
+func evalBitwiseNotPrefixOperatorExpression(right object.Object) object.Object {
+	integer, ok := right.(*object.Integer)
+	if !ok {
+		return newError("unknown operator: ~%s", right.Type())
+	}
+
+	if integer.IsBig() {
+		return object.NewBigInteger(new(big.Int).Not(integer.Big))
+	}
+
+	return &object.Integer{Value: ^integer.Value}
+}
+
+func evalInfixExpression(left object.Object, operator string, right object.Object) object.Object {
+	switch {
+	case operator == ".." || operator == "..=":
+		return evalRangeExpression(left, operator, right)
+	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
+		return evalIntegerInfixExpression(left, operator, right)
+	case isNumeric(left) && isNumeric(right):
+		return evalFloatInfixExpression(left, operator, right)
+	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
+		return evalStringInfixExpression(left, operator, right)
+	case operator == "==":
+		return nativeBoolToBooleanObject(left == right)
+	case operator == "!=":
+		return nativeBoolToBooleanObject(left != right)
+	case left.Type() != right.Type():
+		return newError("type mismatch: %s %s %s", left.Type(), operator, right.Type())
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalRangeExpression(left object.Object, operator string, right object.Object) object.Object {
+	start, ok := left.(*object.Integer)
+	if !ok {
+		return newError("range bounds must be INTEGER, got %s", left.Type())
+	}
+
+	end, ok := right.(*object.Integer)
+	if !ok {
+		return newError("range bounds must be INTEGER, got %s", right.Type())
+	}
+
+	if start.IsBig() || end.IsBig() {
+		return newError("range bounds too large: %s%s%s", start.Inspect(), operator, end.Inspect())
+	}
+
+	return &object.Range{Start: start.Value, End: end.Value, Inclusive: operator == "..="}
+}
+
+// evalIntegerInfixExpression evaluates integer operators using int64
+// arithmetic, falling back to big integer arithmetic when either operand is
+// already big or the int64 result would overflow.
+func evalIntegerInfixExpression(left object.Object, operator string, right object.Object) object.Object {
+	leftInteger := left.(*object.Integer)
+	rightInteger := right.(*object.Integer)
+
+	switch operator {
+	case "**":
+		return evalIntegerPower(leftInteger, rightInteger)
+	case "<<", ">>":
+		return evalIntegerShift(leftInteger, operator, rightInteger)
+	}
+
+	if leftInteger.IsBig() || rightInteger.IsBig() {
+		return evalBigIntegerInfixExpression(leftInteger.BigValue(), operator, rightInteger.BigValue())
+	}
+
+	leftValue := leftInteger.Value
+	rightValue := rightInteger.Value
+
+	switch operator {
+	case "+":
+		sum := leftValue + rightValue
+		if (sum > leftValue) != (rightValue > 0) {
+			return evalBigIntegerInfixExpression(big.NewInt(leftValue), operator, big.NewInt(rightValue))
+		}
+
+		return &object.Integer{Value: sum}
+	case "-":
+		difference := leftValue - rightValue
+		if (difference < leftValue) != (rightValue > 0) {
+			return evalBigIntegerInfixExpression(big.NewInt(leftValue), operator, big.NewInt(rightValue))
+		}
+
+		return &object.Integer{Value: difference}
+	case "*":
+		if leftValue == 0 || rightValue == 0 {
+			return &object.Integer{Value: 0}
+		}
+
+		product := leftValue * rightValue
+		if product/rightValue != leftValue || (leftValue == -1 && rightValue == math.MinInt64) || (rightValue == -1 && leftValue == math.MinInt64) {
+			return evalBigIntegerInfixExpression(big.NewInt(leftValue), operator, big.NewInt(rightValue))
+		}
+
+		return &object.Integer{Value: product}
+	case "/":
+		if leftValue == math.MinInt64 && rightValue == -1 {
+			return evalBigIntegerInfixExpression(big.NewInt(leftValue), operator, big.NewInt(rightValue))
+		}
+
+		if rightValue == 0 {
+			return newError("division by zero")
+		}
+
+		return &object.Integer{Value: leftValue / rightValue}
+	case "%":
+		if rightValue == 0 {
+			return newError("modulo by zero")
+		}
+
+		return &object.Integer{Value: leftValue % rightValue}
+	case "&":
+		return &object.Integer{Value: leftValue & rightValue}
+	case "|":
+		return &object.Integer{Value: leftValue | rightValue}
+	case "^":
+		return &object.Integer{Value: leftValue ^ rightValue}
+	case "<":
+		return nativeBoolToBooleanObject(leftValue < rightValue)
+	case ">":
+		return nativeBoolToBooleanObject(leftValue > rightValue)
+	case "<=":
+		return nativeBoolToBooleanObject(leftValue <= rightValue)
+	case ">=":
+		return nativeBoolToBooleanObject(leftValue >= rightValue)
+	case "==":
+		return nativeBoolToBooleanObject(leftValue == rightValue)
+	case "!=":
+		return nativeBoolToBooleanObject(leftValue != rightValue)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalBigIntegerInfixExpression(leftValue *big.Int, operator string, rightValue *big.Int) object.Object {
+	switch operator {
+	case "+":
+		return object.NewBigInteger(new(big.Int).Add(leftValue, rightValue))
+	case "-":
+		return object.NewBigInteger(new(big.Int).Sub(leftValue, rightValue))
+	case "*":
+		return object.NewBigInteger(new(big.Int).Mul(leftValue, rightValue))
+	case "/":
+		if rightValue.Sign() == 0 {
+			return newError("division by zero")
+		}
+
+		return object.NewBigInteger(new(big.Int).Quo(leftValue, rightValue))
+	case "%":
+		if rightValue.Sign() == 0 {
+			return newError("modulo by zero")
+		}
+
+		return object.NewBigInteger(new(big.Int).Rem(leftValue, rightValue))
+	case "&":
+		return object.NewBigInteger(new(big.Int).And(leftValue, rightValue))
+	case "|":
+		return object.NewBigInteger(new(big.Int).Or(leftValue, rightValue))
+	case "^":
+		return object.NewBigInteger(new(big.Int).Xor(leftValue, rightValue))
+	case "<":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) < 0)
+	case ">":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) > 0)
+	case "<=":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) <= 0)
+	case ">=":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) >= 0)
+	case "==":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) == 0)
+	case "!=":
+		return nativeBoolToBooleanObject(leftValue.Cmp(rightValue) != 0)
+	default:
+		return newError("unknown operator: %s %s %s", object.INTEGER_OBJ, operator, object.INTEGER_OBJ)
+	}
+}
+
+// evalIntegerShift shifts value left or right by count bits. Right shifts
+// are arithmetic, and left shifts promote to a big integer on overflow.
+func evalIntegerShift(value *object.Integer, operator string, count *object.Integer) object.Object {
+	if count.IsBig() {
+		return newError("shift count too large: %s", count.Inspect())
+	}
+
+	if count.Value < 0 {
+		return newError("negative shift count: %d", count.Value)
+	}
+
+	n := uint(count.Value)
+
+	if operator == ">>" {
+		if value.IsBig() {
+			return object.NewBigInteger(new(big.Int).Rsh(value.Big, n))
+		}
+
+		return &object.Integer{Value: value.Value >> n}
+	}
+
+	if !value.IsBig() && n < 63 {
+		shifted := value.Value << n
+		if shifted>>n == value.Value {
+			return &object.Integer{Value: shifted}
+		}
+	}
+
+	bits := value.BigValue().BitLen()
+	if bits > 0 && count.Value > int64(maxIntegerBits-bits) {
+		return newError("integer too large: result of << exceeds %d bits", maxIntegerBits)
+	}
+
+	return object.NewBigInteger(new(big.Int).Lsh(value.BigValue(), n))
+}
+
+// evalIntegerPower raises base to exponent. A negative exponent produces a
+// float as the result is generally fractional.
+func evalIntegerPower(base, exponent *object.Integer) object.Object {
+	if exponent.IsBig() {
+		return newError("exponent too large: %s", exponent.Inspect())
+	}
+
+	if exponent.Value < 0 {
+		return &object.Float{Value: math.Pow(toFloat(base), float64(exponent.Value))}
+	}
+
+	// the result has at most bits * exponent bits; 0, 1 and -1 never grow
+	bits := base.BigValue().BitLen()
+	if bits > 1 && exponent.Value > int64(maxIntegerBits/bits) {
+		return newError("integer too large: result of ** exceeds %d bits", maxIntegerBits)
+	}
+
+	return object.NewBigInteger(new(big.Int).Exp(base.BigValue(), big.NewInt(exponent.Value), nil))
+}
+
+// evalFloatInfixExpression evaluates arithmetic and comparisons where at
+// least one operand is a float, promoting the other operand to a float.
+func evalFloatInfixExpression(left object.Object, operator string, right object.Object) object.Object {
+	leftValue := toFloat(left)
+	rightValue := toFloat(right)
+
+	switch operator {
+	case "+":
+		return &object.Float{Value: leftValue + rightValue}
+	case "-":
+		return &object.Float{Value: leftValue - rightValue}
+	case "*":
+		return &object.Float{Value: leftValue * rightValue}
+	case "/":
+		return &object.Float{Value: leftValue / rightValue}
+	case "%":
+		return &object.Float{Value: math.Mod(leftValue, rightValue)}
+	case "**":
+		return &object.Float{Value: math.Pow(leftValue, rightValue)}
+	case "<":
+		return nativeBoolToBooleanObject(leftValue < rightValue)
+	case ">":
+		return nativeBoolToBooleanObject(leftValue > rightValue)
+	case "<=":
+		return nativeBoolToBooleanObject(leftValue <= rightValue)
+	case ">=":
+		return nativeBoolToBooleanObject(leftValue >= rightValue)
+	case "==":
+		return nativeBoolToBooleanObject(leftValue == rightValue)
+	case "!=":
+		return nativeBoolToBooleanObject(leftValue != rightValue)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalStringInfixExpression(left object.Object, operator string, right object.Object) object.Object {
+	leftValue := left.(*object.String).Value
+	rightValue := right.(*object.String).Value
+
+	switch operator {
+	case "+":
+		return &object.String{Value: leftValue + rightValue}
+	case "<":
+		return nativeBoolToBooleanObject(leftValue < rightValue)
+	case ">":
+		return nativeBoolToBooleanObject(leftValue > rightValue)
+	case "<=":
+		return nativeBoolToBooleanObject(leftValue <= rightValue)
+	case ">=":
+		return nativeBoolToBooleanObject(leftValue >= rightValue)
+	case "==":
+		return nativeBoolToBooleanObject(leftValue == rightValue)
+	case "!=":
+		return nativeBoolToBooleanObject(leftValue != rightValue)
+	default:
+		return newError("unknown operator: %s %s %s", left.Type(), operator, right.Type())
+	}
+}
+
+func evalAssignExpression(node *ast.AssignExpression, env *object.Environment) object.Object {
+	switch target := node.Target.(type) {
+	case *ast.Identifier:
+		value := Eval(node.Value, env)
+		if isUnwinding(value) {
+			return value
+		}
+
+		if node.Operator != "=" {
+			current, ok := env.Get(target.Value)
+			if !ok {
+				return newError("identifier not found: %s", target.Value)
+			}
+
+			value = evalCompoundAssignment(current, node.Operator, value)
+			if isUnwinding(value) {
+				return value
+			}
+		}
+
+		if env.IsConstant(target.Value) {
+			return newError("cannot assign to constant: %s", target.Value)
+		}
+
+		if !env.Assign(target.Value, value) {
+			return newError("cannot assign to undeclared identifier: %s", target.Value)
+		}
+
+		return value
+	case *ast.IndexExpressopn:
+		left := Eval(target.Left, env)
+		if isUnwinding(left) {
+			return left
+		}
+
+		index := Eval(target.Index, env)
+		if isUnwinding(index) {
+			return index
+		}
+
+		return evalElementAssignment(node, left, index, env)
+	case *ast.MemberExpression:
+		left := Eval(target.Object, env)
+		if isUnwinding(left) {
+			return left
+		}
+
+		if left.Type() != object.HASH_OBJ {
+			return newError("member assignment not supported: %s", left.Type())
+		}
+
+		return evalElementAssignment(node, left, &object.String{Value: target.Property.Value}, env)
+	default:
+		return newError("invalid assignment target: %s", node.Target.String())
+	}
+}
+
+// evalElementAssignment evaluates the value of an assignment to left[index]
+// and stores it.
+func evalElementAssignment(node *ast.AssignExpression, left, index object.Object, env *object.Environment) object.Object {
+	value := Eval(node.Value, env)
+	if isUnwinding(value) {
+		return value
+	}
+
+	if node.Operator != "=" {
+		current := evalIndexExpression(left, index)
+		if isUnwinding(current) {
+			return current
+		}
+
+		value = evalCompoundAssignment(current, node.Operator, value)
+		if isUnwinding(value) {
+			return value
+		}
+	}
+
+	return evalIndexAssignment(left, index, value)
+}
+
+// evalCompoundAssignment applies the operator of a compound assignment such
+// as += to the current and assigned values.
+func evalCompoundAssignment(current object.Object, operator string, value object.Object) object.Object {
+	return evalInfixExpression(current, strings.TrimSuffix(operator, "="), value)
+}
+
+func evalIndexAssignment(left, index, value object.Object) object.Object {
+	switch left := left.(type) {
+	case *object.Array:
+		if left.Frozen {
+			return newError("cannot modify frozen %s", left.Type())
+		}
+
+		integer, ok := index.(*object.Integer)
+		if !ok {
+			return newError("array index must be INTEGER, got %s", index.Type())
+		}
+
+		offset, ok := resolveIndex(integer, len(left.Elements))
+		if !ok {
+			return newError("index out of range: %s (length %d)", integer.Inspect(), len(left.Elements))
+		}
+
+		left.Elements[offset] = value
+
+		return value
+	case *object.Hash:
+		if left.Frozen {
+			return newError("cannot modify frozen %s", left.Type())
+		}
+
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", index.Type())
+		}
+
+		left.Pairs[key.HashKey()] = object.HashPair{Key: index, Value: value}
+
+		return value
+	default:
+		return newError("index assignment not supported: %s", left.Type())
+	}
+}
+
+// evalMatchExpression evaluates the body of the first arm whose pattern
+// matches the value and whose guard, if any, holds. Each arm binds its
+// pattern in its own environment so that a failed arm leaves no bindings.
+func evalMatchExpression(node *ast.MatchExpression, env *object.Environment) object.Object {
+	value := Eval(node.Value, env)
+	if isUnwinding(value) {
+		return value
+	}
+
+	for _, arm := range node.Arms {
+		armEnv := object.NewEnclosedEnvironment(env)
+
+		err := destructure(arm.Pattern, value, func(name *ast.Identifier, value object.Object) *object.Error {
+			if name.Value != "_" {
+				armEnv.Set(name.Value, value)
+			}
+
+			return nil
+		})
+		if err != nil {
+			continue
+		}
+
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isUnwinding(guard) {
+				return guard
+			}
+
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+
+		return Eval(arm.Body, armEnv)
+	}
+
+	return newError("no match arm for value: %s", value.Inspect())
+}
+
+// evalTryExpression evaluates the try block, handing an error raised by it to
+// the catch block. The finally block runs however the others finish, and its
+// result replaces theirs only when it raises, returns, breaks or continues.
+func evalTryExpression(node *ast.TryExpression, env *object.Environment) object.Object {
+	result := Eval(node.Block, env)
+
+	if err, ok := result.(*object.Error); ok && node.Catch != nil {
+		catchEnv := object.NewEnclosedEnvironment(env)
+
+		bindErr := destructure(node.Parameter, errorValue(err), func(name *ast.Identifier, value object.Object) *object.Error {
+			catchEnv.Set(name.Value, value)
+			return nil
+		})
+		if bindErr != nil {
+			result = bindErr
+		} else {
+			result = Eval(node.Catch, catchEnv)
+		}
+	}
+
+	if node.Finally != nil {
+		if final := Eval(node.Finally, env); isUnwinding(final) {
+			return final
+		}
+	}
+
+	if result == nil {
+		return NULL
+	}
+
+	return result
+}
+
+// newThrownError builds the error raised by throw. A string is the message,
+// while a hash holds the message and optionally the kind, and its other keys
+// are handed on to catch.
+func newThrownError(value object.Object) *object.Error {
+	switch value := value.(type) {
+	case *object.String:
+		return &object.Error{Message: value.Value, Kind: thrownErrorKind}
+	case *object.Hash:
+		message, ok := hashGet(value, "message")
+		if !ok {
+			return newError("thrown hash has no message")
+		}
+
+		if message.Type() != object.STRING_OBJ {
+			return newError("thrown message must be STRING, got %s", message.Type())
+		}
+
+		err := &object.Error{Message: message.(*object.String).Value, Kind: thrownErrorKind, Details: value}
+
+		if kind, ok := hashGet(value, "kind"); ok {
+			if kind.Type() != object.STRING_OBJ {
+				return newError("thrown kind must be STRING, got %s", kind.Type())
+			}
+
+			err.Kind = kind.(*object.String).Value
+		}
+
+		return err
+	default:
+		return newError("cannot throw %s, want STRING or HASH", value.Type())
+	}
+}
+
+// errorValue is the hash bound by catch, holding the message, kind and stack
+// of err along with any other keys of the hash it was thrown with.
+func errorValue(err *object.Error) *object.Hash {
+	pairs := make(map[object.HashKey]object.HashPair)
+
+	if err.Details != nil {
+		for key, pair := range err.Details.Pairs {
+			pairs[key] = pair
+		}
+	}
+
+	stack := make([]object.Object, len(err.Stack))
+	for i, frame := range err.Stack {
+		stack[i] = &object.String{Value: frame}
+	}
+
+	fields := map[string]object.Object{
+		"message": &object.String{Value: err.Message},
+		"kind":    &object.String{Value: err.Kind},
+		"stack":   &object.Array{Elements: stack},
+	}
+
+	for name, value := range fields {
+		key := &object.String{Value: name}
+		pairs[key.HashKey()] = object.HashPair{Key: key, Value: value}
+	}
+
+	return &object.Hash{Pairs: pairs}
+}
+
+// hashGet looks up the value of a string key in hash.
+func hashGet(hash *object.Hash, key string) (object.Object, bool) {
+	pair, ok := hash.Pairs[(&object.String{Value: key}).HashKey()]
+	return pair.Value, ok
+}
+
+// evalLogicalExpression evaluates && and ||, only evaluating the right
+// operand when the left does not decide the result. The deciding operand is
+// returned as is rather than converted to a boolean.
+func evalLogicalExpression(node *ast.LogicalExpression, env *object.Environment) object.Object {
+	left := Eval(node.Left, env)
+	if isUnwinding(left) {
+		return left
+	}
+
+	switch node.Operator {
+	case "&&":
+		if !isTruthy(left) {
+			return left
+		}
+	case "||":
+		if isTruthy(left) {
+			return left
+		}
+	case "??":
+		if left != NULL {
+			return left
+		}
+	default:
+		return newError("unknown operator: %s", node.Operator)
+	}
+
+	return Eval(node.Right, env)
+}
+
+func evalIfExpression(ie *ast.IfExpression, env *object.Environment) object.Object {
+	condition := Eval(ie.Condition, env)
+	if isUnwinding(condition) {
+		return condition
+	}
+
+	if isTruthy(condition) {
+		return evalBlockStatement(ie.Consequence, env)
+	} else if ie.Alternative != nil {
+		return evalBlockStatement(ie.Alternative, env)
+	}
+
+	return NULL
+}
+
+func evalIdentifier(node *ast.Identifier, env *object.Environment) object.Object {
+	if val, ok := env.Get(node.Value); ok {
+		return val
+	}
+
+	if builtin, ok := builtins[node.Value]; ok {
+		return builtin
+	}
+
+	return newError(fmt.Sprintf("identifier not found: %s", node.Value))
+}
+
+func evalExpressions(exps []ast.Expression, env *object.Environment) []object.Object {
+	var result []object.Object
+
+	for _, e := range exps {
+		if spread, ok := e.(*ast.SpreadElement); ok {
+			evaluated := Eval(spread.Value, env)
+			if isUnwinding(evaluated) {
+				return []object.Object{evaluated}
+			}
+
+			array, ok := evaluated.(*object.Array)
+			if !ok {
+				err := newError("cannot spread %s", evaluated.Type())
+				err.Pos = spread.Pos()
+
+				return []object.Object{err}
+			}
+
+			result = append(result, array.Elements...)
+			continue
+		}
+
+		evaluated := Eval(e, env)
+		if isUnwinding(evaluated) {
+			return []object.Object{evaluated}
+		}
+		result = append(result, evaluated)
+	}
+
+	return result
+}
+
+func evalIndexExpression(left, index object.Object) object.Object {
+	switch {
+	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalArrayIndexExpression(left, index)
+	case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalStringIndexExpression(left, index)
+	case left.Type() == object.HASH_OBJ:
+		return evalHashIndexExpression(left, index)
+	default:
+		return newError("index operator not supported: %s", left.Type())
+	}
+}
+
+func evalArrayIndexExpression(array, index object.Object) object.Object {
+	arrayObj := array.(*object.Array)
+
+	idx, ok := resolveIndex(index.(*object.Integer), len(arrayObj.Elements))
+	if !ok {
+		return NULL
+	}
+
+	return arrayObj.Elements[idx]
+}
+
+// resolveIndex converts index into an offset into a sequence of the given
+// length, counting back from the end when negative. ok is false when the
+// index is out of range.
+func resolveIndex(index *object.Integer, length int) (offset int, ok bool) {
+	if index.IsBig() {
+		return 0, false
+	}
+
+	i := index.Value
+	if i < 0 {
+		i += int64(length)
+	}
+
+	if i < 0 || i >= int64(length) {
+		return 0, false
+	}
+
+	return int(i), true
+}
+
+func evalSliceExpression(node *ast.SliceExpression, env *object.Environment) (object.Object, bool) {
+	left, skipped := evalChainOperand(node.Left, node.Optional, env)
+	if skipped || isUnwinding(left) {
+		return left, skipped
+	}
+
+	bounds := []object.Object{nil, nil}
+	for i, bound := range []ast.Expression{node.Low, node.High} {
+		if bound == nil {
+			continue
+		}
+
+		bounds[i] = Eval(bound, env)
+		if isUnwinding(bounds[i]) {
+			return bounds[i], false
+		}
+	}
+
+	switch left := left.(type) {
+	case *object.Array:
+		start, end, err := sliceBounds(bounds[0], bounds[1], len(left.Elements))
+		if err != nil {
+			return err, false
+		}
+
+		elements := make([]object.Object, end-start)
+		copy(elements, left.Elements[start:end])
+
+		return &object.Array{Elements: elements}, false
+	case *object.String:
+		runes := []rune(left.Value)
+
+		start, end, err := sliceBounds(bounds[0], bounds[1], len(runes))
+		if err != nil {
+			return err, false
+		}
+
+		return &object.String{Value: string(runes[start:end])}, false
+	default:
+		return newError("slice operator not supported: %s", left.Type()), false
+	}
+}
+
+// sliceBounds resolves the bounds of a slice of a sequence of the given
+// length. Omitted bounds are nil and default to the whole sequence; negative
+// bounds count back from the end and out of range bounds are clamped.
+func sliceBounds(start, end object.Object, length int) (int, int, *object.Error) {
+	resolve := func(bound object.Object, omitted int) (int, *object.Error) {
+		if bound == nil {
+			return omitted, nil
+		}
+
+		integer, ok := bound.(*object.Integer)
+		if !ok {
+			return 0, newError("slice index must be INTEGER, got %s", bound.Type())
+		}
+
+		if integer.IsBig() {
+			if integer.Big.Sign() < 0 {
+				return 0, nil
+			}
+
+			return length, nil
+		}
+
+		i := integer.Value
+		if i < 0 {
+			i += int64(length)
+		}
+
+		return int(max(0, min(i, int64(length)))), nil
+	}
+
+	from, err := resolve(start, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	to, err := resolve(end, length)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return from, max(from, to), nil
+}
+
+// evalStringIndexExpression indexes a string by character rather than by
+// byte, returning the character as a single character string.
+func evalStringIndexExpression(str, index object.Object) object.Object {
+	runes := []rune(str.(*object.String).Value)
+
+	idx, ok := resolveIndex(index.(*object.Integer), len(runes))
+	if !ok {
+		return NULL
+	}
+
+	return &object.String{Value: string(runes[idx])}
+}
+
+func evalHashIndexExpression(array, index object.Object) object.Object {
+	hashObj := array.(*object.Hash)
+
+	key, ok := index.(object.Hashable)
+	if !ok {
+		return newError("unusable as hash key: %s", index.Type())
+	}
+
+	pair, ok := hashObj.Pairs[key.HashKey()]
+	if !ok {
+		return NULL
+	}
+
+	return pair.Value
+}
+
+func evalHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
+	pairs := make(map[object.HashKey]object.HashPair)
+
+	for keyNode, valueNode := range node.Pairs {
+		key := Eval(keyNode, env)
+		if isUnwinding(key) {
+			return key
+		}
+
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return newError("unusable as hash key: %s", key.Type())
+		}
+
+		value := Eval(valueNode, env)
+		if isUnwinding(value) {
+			return value
+		}
+
+		hashed := hashKey.HashKey()
+		pairs[hashed] = object.HashPair{Key: key, Value: value}
 	}
 
 	return &object.Hash{Pairs: pairs}
@@ -493,6 +1776,26 @@ func nativeBoolToBooleanObject(input bool) *object.Boolean {
 	}
 }
 
+func isNumeric(obj object.Object) bool {
+	return obj.Type() == object.INTEGER_OBJ || obj.Type() == object.FLOAT_OBJ
+}
+
+func toFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		if obj.IsBig() {
+			value, _ := new(big.Float).SetInt(obj.Big).Float64()
+			return value
+		}
+
+		return float64(obj.Value)
+	case *object.Float:
+		return obj.Value
+	default:
+		return 0
+	}
+}
+
 func isTruthy(obj object.Object) bool {
 	switch obj {
 	case NULL:
@@ -2,6 +2,7 @@ package evaluator
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/Jamess-Lucass/interpreter-go/lexer"
@@ -26,6 +27,15 @@ func testIntegerObject(t *testing.T, obj object.Object, expected int64) {
 	assert.Equal(t, expected, result.Value)
 }
 
+func testFloatObject(t *testing.T, obj object.Object, expected float64) {
+	result, ok := obj.(*object.Float)
+	assert.True(t, ok)
+
+	if ok {
+		assert.InDelta(t, expected, result.Value, 1e-9)
+	}
+}
+
 func Test_EvalIntgerExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -331,6 +341,8 @@ func Test_BuiltinFunctions(t *testing.T) {
 		{`len("")`, 0},
 		{`len("four")`, 4},
 		{`len("hello world")`, 11},
+		{`len("héllo")`, 5},
+		{`len("👋🌍")`, 2},
 		{`len(1)`, "argument to `len` not supported, got INTEGER"},
 		{`len("one", "two")`, "wrong number of arguments. got=2, want=1"},
 	}
@@ -405,6 +417,14 @@ func Test_ArrayIndexExpressions(t *testing.T) {
 		},
 		{
 			"[1, 2, 3][-1]",
+			3,
+		},
+		{
+			"[1, 2, 3][-3]",
+			1,
+		},
+		{
+			"[1, 2, 3][-4]",
 			nil,
 		},
 	}
@@ -476,3 +496,1300 @@ func Test_HashIndexExpression(t* testing.T) {
 		}
 	}
 }
+
+func Test_ErrorPositions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"foobar", "ERROR: 1:1: identifier not found: foobar"},
+		{"let x = 1;\nx + true;", "ERROR: 2:1: type mismatch: INTEGER + BOOLEAN"},
+		{"let f = fn() {\n\t-true\n};\nf();", "ERROR: 2:2: unknown operator: -BOOLEAN"},
+		{`len(1)`, "ERROR: 1:1: argument to `len` not supported, got INTEGER"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok)
+
+		assert.Equal(t, test.expected, result.Inspect())
+	}
+}
+
+func Test_StringIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"hello"[0]`, "h"},
+		{`"héllo"[1]`, "é"},
+		{`"👋🌍"[1]`, "🌍"},
+		{`let café = "crème"; café[2]`, "è"},
+		{`"abc"[3]`, nil},
+		{`"abc"[-1]`, "c"},
+		{`"héllo"[-4]`, "é"},
+		{`"abc"[-4]`, nil},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+
+		str, ok := test.expected.(string)
+		if ok {
+			result, ok := evaluated.(*object.String)
+			assert.True(t, ok)
+			assert.Equal(t, str, result.Value)
+		} else {
+			assert.Equal(t, NULL, evaluated)
+		}
+	}
+}
+
+func Test_EvalFloatExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"3.14", 3.14},
+		{"-2.5", -2.5},
+		{"1.5 + 1.5", 3},
+		{"1 + 0.5", 1.5},
+		{"0.5 + 1", 1.5},
+		{"10 / 4.0", 2.5},
+		{"2 * 1.25 - 1", 1.5},
+		{"let values = [1, 2.5, 4]; (values[0] + values[1] + values[2]) / 3", 2.5},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		testFloatObject(t, evaluated, test.expected)
+	}
+}
+
+func Test_EvalFloatComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"1.5 < 2", true},
+		{"2 > 1.5", true},
+		{"1.0 == 1", true},
+		{"1 != 1.0", false},
+		{"0.1 + 0.2 == 0.3", false},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Boolean)
+		assert.True(t, ok)
+
+		assert.Equal(t, test.expected, result.Value, fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_FloatInspect(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"3.14", "3.14"},
+		{"2.0", "2.0"},
+		{"1 / 2.0", "0.5"},
+		{"1e21", "1e+21"},
+		{"1e-9", "1e-09"},
+		{"100000.0", "100000.0"},
+		{"-0.5", "-0.5"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect())
+
+		roundTrip := testEval(evaluated.Inspect())
+		assert.Equal(t, evaluated, roundTrip)
+	}
+}
+
+func Test_IntegerOverflowPromotion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"9223372036854775807 + 1", "9223372036854775808"},
+		{"-9223372036854775807 - 2", "-9223372036854775809"},
+		{"4294967296 * 4294967296", "18446744073709551616"},
+		{"-(-9223372036854775807 - 1)", "9223372036854775808"},
+		{"(-9223372036854775807 - 1) / -1", "9223372036854775808"},
+		{"(9223372036854775807 + 1) - 1", "9223372036854775807"},
+		{"100000000000000000000", "100000000000000000000"},
+		{"-9223372036854775808", "-9223372036854775808"},
+		{"0x1_0000_0000_0000_0000 - 1", "18446744073709551615"},
+		{"9223372036854775808 - 1", "9223372036854775807"},
+		{"let f = fn() { 18446744073709551616 }; f() + f()", "36893488147419103232"},
+		{`
+		let factorial = fn(n) { if (n < 2) { 1 } else { n * factorial(n - 1) } };
+		factorial(25)
+		`, "15511210043330985984000000"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Integer)
+		assert.True(t, ok)
+
+		assert.Equal(t, test.expected, result.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_BigIntegerDemotion(t *testing.T) {
+	evaluated := testEval("(9223372036854775807 + 10) - 20")
+	result, ok := evaluated.(*object.Integer)
+	assert.True(t, ok)
+
+	assert.False(t, result.IsBig())
+	testIntegerObject(t, result, 9223372036854775797)
+}
+
+func Test_BigIntegerLiteralRoundTrip(t *testing.T) {
+	for _, input := range []string{"2 ** 100", "-(2 ** 70)", "-9223372036854775807 - 1"} {
+		evaluated := testEval(input)
+		roundTrip := testEval(evaluated.Inspect())
+
+		assert.Equal(t, evaluated, roundTrip, fmt.Sprintf("for input: %v", input))
+	}
+
+	result, ok := testEval("-9223372036854775808").(*object.Integer)
+	assert.True(t, ok)
+	assert.False(t, result.IsBig())
+}
+
+func Test_BigIntegerComparisons(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"9223372036854775807 + 1 > 9223372036854775807", true},
+		{"9223372036854775807 < 9223372036854775807 + 1", true},
+		{"9223372036854775807 + 1 == 9223372036854775807 + 1", true},
+		{"9223372036854775807 + 1 != 9223372036854775807 + 2", true},
+		{"(9223372036854775807 + 1) - 1 == 9223372036854775807", true},
+		{"9223372036854775807 + 1 > 1.5", true},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Boolean)
+		assert.True(t, ok)
+
+		assert.Equal(t, test.expected, result.Value, fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_BigIntegerHashKeys(t *testing.T) {
+	input := `let h = {9223372036854775807 + 1: "big", 1: "small"};
+	[h[9223372036854775806 + 2], h[(9223372036854775807 + 2) - (9223372036854775807 + 1)]]`
+
+	evaluated := testEval(input)
+	result, ok := evaluated.(*object.Array)
+	assert.True(t, ok)
+
+	assert.Equal(t, "[big, small]", result.Inspect())
+}
+
+func Test_ModuloOperator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"10 % 3", 1},
+		{"-10 % 3", -1},
+		{"10 % -3", 1},
+		{"2 + 10 % 4 * 3", 8},
+		{"(9223372036854775807 + 2) % 10", 9},
+		{"(-9223372036854775807 - 1) % -1", 0},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		testIntegerObject(t, evaluated, test.expected)
+	}
+}
+
+func Test_DivisionByZero(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 / 0", "division by zero"},
+		{"1 % 0", "modulo by zero"},
+		{"let zero = 0; 10 / zero; 5", "division by zero"},
+		{"(9223372036854775807 + 1) / 0", "division by zero"},
+		{"(9223372036854775807 + 1) % 0", "modulo by zero"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok)
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
+
+func Test_MaximumCallDepth(t *testing.T) {
+	evaluated := testEval("let f = fn(n) { f(n + 1) }; f(0);")
+	result, ok := evaluated.(*object.Error)
+	assert.True(t, ok)
+	assert.Equal(t, "maximum call depth of 10000 exceeded", result.Message)
+
+	evaluated = testEval("let sum = fn(n) { if (n == 0) { 0 } else { n + sum(n - 1) } }; sum(5000);")
+	testIntegerObject(t, evaluated, 12502500)
+
+	heavy := `let f = fn(n) {
+	if (n == 0) { return 0 };
+	for (i in [1]) {
+		while (true) {
+			let r = try {
+				match ([n]) {
+					[m] => if (true) { [{"a": [-(-((m - 1) |> f(...[]))) + 1]}][0]["a"][0] }
+				}
+			} catch (e) { throw e } finally { 0 };
+			return r
+		}
+	}
+};
+`
+
+	evaluated = testEval(heavy + "f(9990);")
+	testIntegerObject(t, evaluated, 9990)
+
+	evaluated = testEval(heavy + "f(10000);")
+	result, ok = evaluated.(*object.Error)
+	assert.True(t, ok)
+	assert.Equal(t, "maximum call depth of 10000 exceeded", result.Message)
+}
+
+func Test_PanicsBecomeErrors(t *testing.T) {
+	builtins["explode"] = &object.Builtin{
+		Fn: func(_ object.Keywords, args ...object.Object) object.Object {
+			panic("boom")
+		},
+	}
+	defer delete(builtins, "explode")
+
+	evaluated := testEval("1; explode();")
+	result, ok := evaluated.(*object.Error)
+	assert.True(t, ok)
+	assert.Equal(t, "internal error: boom", result.Message)
+}
+
+func Test_FunctionArity(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let add = fn(a, b) { a + b }; add(1);", "wrong number of arguments to `add`: want=2, got=1"},
+		{"let add = fn(a, b) { a + b }; add(1, 2, 3);", "wrong number of arguments to `add`: want=2, got=3"},
+		{"let f = fn() { 1 }; f(1);", "wrong number of arguments to `f`: want=0, got=1"},
+		{"fn(x) { x }();", "wrong number of arguments to anonymous function: want=1, got=0"},
+		{"let add = fn(a, b) { a + b }; let plus = add; plus(1);", "wrong number of arguments to `add`: want=2, got=1"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok)
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
+
+func Test_ComparisonOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"1 <= 2", true},
+		{"2 <= 2", true},
+		{"3 <= 2", false},
+		{"1 >= 2", false},
+		{"2 >= 2", true},
+		{"1.5 <= 1.5", true},
+		{"2 >= 2.5", false},
+		{"9223372036854775807 + 1 >= 9223372036854775807 + 1", true},
+		{"9223372036854775807 + 1 <= 9223372036854775807", false},
+		{`"apple" < "banana"`, true},
+		{`"apple" > "banana"`, false},
+		{`"apple" <= "apple"`, true},
+		{`"b" >= "apple"`, true},
+		{`"apple" == "apple"`, true},
+		{`"apple" != "apple"`, false},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Boolean)
+		assert.True(t, ok)
+
+		assert.Equal(t, test.expected, result.Value, fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_PowerOperator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"2 ** 10", "1024"},
+		{"2 ** 3 ** 2", "512"},
+		{"-2 ** 2", "-4"},
+		{"(-2) ** 2", "4"},
+		{"2 * 3 ** 2", "18"},
+		{"2 ** 100", "1267650600228229401496703205376"},
+		{"1 ** 999999999", "1"},
+		{"(-1) ** 999999999", "-1"},
+		{"0 ** 999999999", "0"},
+		{"2 ** -1", 0.5},
+		{"2.0 ** 0.5", 1.4142135623730951},
+		{"9 ** 0.5", 3.0},
+		{"7.5 % 2", 1.5},
+		{"-7.5 % 2", -1.5},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+
+		switch expected := test.expected.(type) {
+		case string:
+			result, ok := evaluated.(*object.Integer)
+			assert.True(t, ok)
+			assert.Equal(t, expected, result.Inspect(), fmt.Sprintf("for input: %v", test.input))
+		case float64:
+			testFloatObject(t, evaluated, expected)
+		}
+	}
+}
+
+func Test_PowerOperatorErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"3 ** 999999999", "integer too large: result of ** exceeds 1048576 bits"},
+		{"(2 ** 64) ** 20000", "integer too large: result of ** exceeds 1048576 bits"},
+		{"2 ** (2 ** 64)", "exponent too large: 18446744073709551616"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok, fmt.Sprintf("for input: %v", test.input))
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
+
+func Test_LogicalOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"true && true", true},
+		{"true && false", false},
+		{"false || true", true},
+		{"false || false", false},
+		{"1 < 2 && 2 < 3", true},
+		{"1 > 2 || 2 > 3", false},
+		{"1 && 2", 2},
+		{"0 || 5", 0},
+		{`if (false) { 1 } || "default"`, "default"},
+		{"false && 1", false},
+		{"false || 3", 3},
+		{"true || 1 / 0", true},
+		{"false && undefinedName", false},
+		{"let calls = fn() { explode }; true || calls()", true},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+
+		switch expected := test.expected.(type) {
+		case bool:
+			assert.Equal(t, nativeBoolToBooleanObject(expected), evaluated, fmt.Sprintf("for input: %v", test.input))
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			result, ok := evaluated.(*object.String)
+			assert.True(t, ok)
+			assert.Equal(t, expected, result.Value)
+		}
+	}
+}
+
+func Test_LogicalOperatorErrors(t *testing.T) {
+	evaluated := testEval("true && 1 / 0")
+	result, ok := evaluated.(*object.Error)
+	assert.True(t, ok)
+	assert.Equal(t, "division by zero", result.Message)
+}
+
+func Test_BitwiseOperators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0b1100 & 0b1010", "8"},
+		{"0b1100 | 0b1010", "14"},
+		{"0b1100 ^ 0b1010", "6"},
+		{"~0", "-1"},
+		{"~5", "-6"},
+		{"1 << 10", "1024"},
+		{"1024 >> 3", "128"},
+		{"-16 >> 2", "-4"},
+		{"0xCAFE >> 8 & 0xFF", "202"},
+		{"1 << 64", "18446744073709551616"},
+		{"(1 << 64) >> 63", "2"},
+		{"(1 << 64) | 1", "18446744073709551617"},
+		{"(1 << 64) & 0xFF", "0"},
+		{"~(1 << 64)", "-18446744073709551617"},
+		{"0 << 99999999999", "0"},
+		{"((1 << 1048575) >> 1048574)", "2"},
+		{"-1 << 63", "-9223372036854775808"},
+		{"5 >> 100", "0"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Integer)
+		assert.True(t, ok)
+
+		assert.Equal(t, test.expected, result.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_BitwiseOperatorErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 << -1", "negative shift count: -1"},
+		{"1 >> -3", "negative shift count: -3"},
+		{"1 << (1 << 64)", "shift count too large: 18446744073709551616"},
+		{"1 << 99999999999", "integer too large: result of << exceeds 1048576 bits"},
+		{"(1 << 1048575) << 1", "integer too large: result of << exceeds 1048576 bits"},
+		{"~true", "unknown operator: ~BOOLEAN"},
+		{"1.5 & 1", "unknown operator: FLOAT & INTEGER"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok)
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
+
+func Test_WhileLoops(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let f = fn(n) { while (n > 0) { return n; } }; f(3);", 3},
+		{"let f = fn() { while (true) { break; } 7 }; f();", 7},
+		{"while (false) { 1 }", nil},
+		{"let f = fn() { while (1 / 0) { } }; f();", "division by zero"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+
+		switch expected := test.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			result, ok := evaluated.(*object.Error)
+			assert.True(t, ok)
+			assert.Equal(t, expected, result.Message)
+		default:
+			assert.Equal(t, NULL, evaluated)
+		}
+	}
+}
+
+func Test_ForInLoops(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let f = fn() { for (x in [1, 2, 3]) { if (x == 2) { return x; } } }; f();", "2"},
+		{"let f = fn() { for (x in [1, 2, 3]) { if (x > 1) { break; } return x; } }; f();", "1"},
+		{"let f = fn() { for (c in \"héllo\") { if (c != \"h\") { return c; } } }; f();", "é"},
+		{"let f = fn() { for (k in {\"b\": 2, \"a\": 1}) { return k; } }; f();", "a"},
+		{"let f = fn() { for (k in {3: 0, -1: 0, 2: 0}) { return k; } }; f();", "-1"},
+		{"let f = fn() { for (x in [1, 2, 3]) { if (x < 3) { continue; } return x; } }; f();", "3"},
+		{"let f = fn() { for (x in []) { return 1; } 0 }; f();", "0"},
+		{`let f = fn() {
+			for (row in [[1, 2], [3, 4]]) {
+				for (x in row) {
+					if (x == 3) { return x; }
+					if (x == 1) { break; }
+				}
+			}
+		};
+		f();`, "3"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_LoopControlInExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let i = 0; while (true) { let stop = if (i > 3) { break; } else { false }; i += 1 }; i", "4"},
+		{"let out = []; for (x in [1, 2, 3]) { out = push(out, if (x == 2) { continue; } else { x }) }; out", "[1, 3]"},
+		{"let out = []; for (x in [1, 2, 3]) { out = push(out, [if (x == 2) { break; } else { x }]) }; out", "[[1]]"},
+		{"let n = 0; for (x in [1, 2, 3]) { n = n + if (x == 2) { continue; } else { x } }; n", "4"},
+		{"let h = {}; for (x in [1, 2]) { h[x] = {\"v\": if (x == 2) { break; } else { x }} }; h", "{1: {v: 1}}"},
+		{"let i = 0; while (i < 5) { i += 1; len([\"a\"][if (i < 5) { continue; } else { 0 }]) }; i", "5"},
+		{"let f = fn() { let x = if (true) { return 5; } else { 1 }; 10 }; f();", "5"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_ForInLoopErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"for (x in 5) { x }", "cannot iterate over INTEGER"},
+		{"for (x in [1, 0]) { 1 / x }", "division by zero"},
+		{"for (x in y) { x }", "identifier not found: y"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok)
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
+
+func Test_Assignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x = 1; x = 2; x", "2"},
+		{"let x = 1; x = x + 1", "2"},
+		{"let x = 1; let y = 2; x = y = 3; x + y", "6"},
+		{"let x = 10; x += 5; x -= 3; x *= 2; x /= 4; x", "6"},
+		{"let s = \"a\"; s += \"b\"; s", "ab"},
+		{"let x = 1; let f = fn() { x = 5; }; f(); x", "5"},
+		{"let x = 1; let f = fn() { let x = 2; x = 5; }; f(); x", "1"},
+		{"let sum = 0; let i = 0; while (i < 5) { i += 1; sum += i; } sum", "15"},
+		{"let sum = 0; for (x in [1, 2, 3]) { sum += x * x; } sum", "14"},
+		{"let a = [1, 2, 3]; a[1] = 5; a", "[1, 5, 3]"},
+		{"let a = [1, 2, 3]; a[0] += 10; a[0]", "11"},
+		{"let a = [[1], [2]]; a[1][0] = 7; a", "[[1], [7]]"},
+		{"let h = {}; h[\"a\"] = 1; h[\"a\"] += 1; h[\"a\"]", "2"},
+		{"let h = {\"n\": 1}; let g = fn(m) { m[\"n\"] = 9; }; g(h); h[\"n\"]", "9"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_AssignmentErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"x = 1", "cannot assign to undeclared identifier: x"},
+		{"x += 1", "identifier not found: x"},
+		{"let x = 1; x += \"a\"", "type mismatch: INTEGER + STRING"},
+		{"let x = 1; x /= 0", "division by zero"},
+		{"let a = [1]; a[1] = 2", "index out of range: 1 (length 1)"},
+		{"let a = [1]; a[-2] = 2", "index out of range: -2 (length 1)"},
+		{"let a = [1]; a[\"0\"] = 2", "array index must be INTEGER, got STRING"},
+		{"let h = {}; h[fn() {}] = 1", "unusable as hash key: FUNCTION"},
+		{"let s = \"abc\"; s[0] = \"x\"", "index assignment not supported: STRING"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok, fmt.Sprintf("for input: %v", test.input))
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
+
+func Test_Constants(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"const x = 5; x * 2", "10"},
+		{"const x = 5; let f = fn() { let x = 1; x = 2; x }; f() + x", "7"},
+		{"const x = 5; let f = fn() { const x = 1; x }; f() + x", "6"},
+		{"let x = 1; const x = 2; x", "2"},
+		{"const a = [1, 2]; let b = push(a, 3); b", "[1, 2, 3]"},
+		{"const a = [1, 2]; a[0] = 9; a", "[9, 2]"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_Freeze(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"freeze([1, 2, 3])", "[1, 2, 3]"},
+		{"let a = [1, [2]]; freeze(a); push(a, 3)", "[1, [2], 3]"},
+		{"freeze(5)", "5"},
+		{"let a = [1]; a[0] = a; freeze(a); len(a)", "1"},
+		{"let h = freeze({\"a\": 1}); h[\"a\"]", "1"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_ImmutabilityErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"const x = 1; x = 2", "cannot assign to constant: x"},
+		{"const x = 1; x += 2", "cannot assign to constant: x"},
+		{"const x = 1; let f = fn() { x = 2; }; f()", "cannot assign to constant: x"},
+		{"const x = 1; const x = 2", "cannot redeclare constant: x"},
+		{"const x = 1; let x = 2", "cannot redeclare constant: x"},
+		{"let a = freeze([1, 2]); a[0] = 3", "cannot modify frozen ARRAY"},
+		{"let h = freeze({\"a\": 1}); h[\"b\"] = 2", "cannot modify frozen HASH"},
+		{"let a = freeze([[1], {\"k\": [2]}]); a[0][0] = 3", "cannot modify frozen ARRAY"},
+		{"let a = freeze([[1], {\"k\": [2]}]); a[1][\"k\"][0] = 3", "cannot modify frozen ARRAY"},
+		{"freeze()", "wrong number of arguments. got=0, want=1"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok, fmt.Sprintf("for input: %v", test.input))
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
+
+func Test_Destructuring(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b] = [1, 2]; a + b", "3"},
+		{"let [a, ...rest] = [1, 2, 3]; rest", "[2, 3]"},
+		{"let [a, ...rest] = [1]; rest", "[]"},
+		{"let [...all] = [1, 2]; all", "[1, 2]"},
+		{"let [a, [b, c]] = [1, [2, 3]]; a + b + c", "6"},
+		{"let {name, age: years} = {\"name\": \"Ann\", \"age\": 30}; name", "Ann"},
+		{"let {name, age: years} = {\"name\": \"Ann\", \"age\": 30}; years", "30"},
+		{"let {\"full name\": n} = {\"full name\": \"Ann Lee\"}; n", "Ann Lee"},
+		{"let {point: [x, y]} = {\"point\": [3, 4]}; x * y", "12"},
+		{"let a = [1, 2]; let [x, ...rest] = a; rest[0] = 9; a", "[1, 2]"},
+		{"let add = fn([a, b]) { a + b }; add([2, 3])", "5"},
+		{"let greet = fn({name}, greeting) { greeting + \" \" + name }; greet({\"name\": \"Bo\"}, \"hi\")", "hi Bo"},
+		{"let f = fn([head, ...tail]) { len(tail) }; f([1, 2, 3])", "2"},
+		{"const [a, b] = [1, 2]; a + b", "3"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_DestructuringErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b] = 5;", "cannot destructure INTEGER as array"},
+		{"let [a, b] = [1];", "array pattern expects 2 elements, got 1"},
+		{"let [a, b] = [1, 2, 3];", "array pattern expects 2 elements, got 3"},
+		{"let [a, b, ...c] = [1];", "array pattern expects at least 2 elements, got 1"},
+		{"let {a} = [1];", "cannot destructure ARRAY as hash"},
+		{"let {a} = {\"b\": 1};", "hash has no key \"a\""},
+		{"let {a: [b]} = {\"a\": 1};", "cannot destructure INTEGER as array"},
+		{"const [a] = [1]; let [a] = [2];", "cannot redeclare constant: a"},
+		{"const [a, b] = [1, 2]; b = 3;", "cannot assign to constant: b"},
+		{"let f = fn([a, b]) { a }; f([1]);", "array pattern expects 2 elements, got 1"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok, fmt.Sprintf("for input: %v", test.input))
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
+
+func Test_DestructuringErrorPositions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, [b]] = [1, 2];", "1:9"},
+		{"let {a, b} = {\"a\": 1};", "1:9"},
+		{"let f = fn(x, [y]) { y };\nf(1, 2);", "1:15"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok, fmt.Sprintf("for input: %v", test.input))
+
+		assert.Equal(t, test.expected, result.Pos.String())
+	}
+}
+
+func Test_DefaultAndRestParameters(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let f = fn(a, b = 10) { a + b }; f(1)", "11"},
+		{"let f = fn(a, b = 10) { a + b }; f(1, 2)", "3"},
+		{"let f = fn(a, b = a * 2) { b }; f(4)", "8"},
+		{"let n = 1; let f = fn(a = n) { a }; n = 5; f()", "5"},
+		{"let f = fn(a = []) { push(a, 1) }; f(); f()", "[1]"},
+		{"let f = fn(h = {}) { let old = h[\"k\"]; h[\"k\"] = 1; old }; f(); f()", "null"},
+		{"let make = fn() { let k = 3; fn(a = k) { a } }; let k = 7; make()()", "3"},
+		{"let f = fn(...rest) { rest }; f()", "[]"},
+		{"let f = fn(a, ...rest) { rest }; f(1, 2, 3)", "[2, 3]"},
+		{"let f = fn(a, b = 2, ...rest) { [a, b, rest] }; f(1)", "[1, 2, []]"},
+		{"let f = fn(a, b = 2, ...rest) { [a, b, rest] }; f(1, 5, 6, 7)", "[1, 5, [6, 7]]"},
+		{"let f = fn([a, b] = [1, 2]) { a + b }; f()", "3"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_Spread(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a = [1, 2]; let b = [3]; [...a, ...b]", "[1, 2, 3]"},
+		{"[0, ...[], 1]", "[0, 1]"},
+		{"let a = [1, 2]; let b = [...a]; b[0] = 9; a", "[1, 2]"},
+		{"let add = fn(a, b, c) { a + b + c }; add(...[1, 2, 3])", "6"},
+		{"let add = fn(a, b, c) { a + b + c }; add(1, ...[2, 3])", "6"},
+		{"let f = fn(...rest) { rest }; f(...[1, 2], 3, ...[4])", "[1, 2, 3, 4]"},
+		{"len(...[\"abc\"])", "3"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_ParameterAndSpreadErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let f = fn(a, b = 1) { a }; f()", "wrong number of arguments to `f`: want=1..2, got=0"},
+		{"let f = fn(a, b = 1) { a }; f(1, 2, 3)", "wrong number of arguments to `f`: want=1..2, got=3"},
+		{"let f = fn(a, ...rest) { a }; f()", "wrong number of arguments to `f`: want>=1, got=0"},
+		{"let f = fn(a = 1 / 0) { a }; f()", "division by zero"},
+		{"let f = fn(a = missing) { a }; f()", "identifier not found: missing"},
+		{"[...5]", "cannot spread INTEGER"},
+		{"let f = fn(a) { a }; f(...\"ab\")", "cannot spread STRING"},
+		{"let f = fn(a) { a }; f(...[1, 2])", "wrong number of arguments to `f`: want=1, got=2"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok, fmt.Sprintf("for input: %v", test.input))
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
+
+func Test_KeywordArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let f = fn(a, b) { a - b }; f(b: 1, a: 5)", "4"},
+		{"let f = fn(a, b) { a - b }; f(5, b: 1)", "4"},
+		{"let f = fn(a, verbose = false, dry = false) { [a, verbose, dry] }; f(1, dry: true)", "[1, false, true]"},
+		{"let f = fn(a, b = a + 1, c = b * 2) { c }; f(1, b: 10)", "20"},
+		{"let f = fn(a, ...rest) { [a, rest] }; f(a: 1)", "[1, []]"},
+		{"let f = fn([x, y], scale) { (x + y) * scale }; f([1, 2], scale: 3)", "9"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_KeywordArgumentErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let f = fn(a) { a }; f(1, verbose: true)", "unknown keyword argument to `f`: verbose"},
+		{"let f = fn(a, b) { a }; f(1, 2, a: 3)", "multiple values for argument a to `f`"},
+		{"let f = fn(a, b) { a }; f(1, 2, 3, a: 4)", "multiple values for argument a to `f`"},
+		{"let f = fn(a, b) { a }; f(1, b: 2, c: 3)", "unknown keyword argument to `f`: c"},
+		{"let f = fn(a, b) { a }; f(b: 2)", "wrong number of arguments to `f`: want=2, got=1"},
+		{"let f = fn(a, b) { a }; f(1, 2, 3)", "wrong number of arguments to `f`: want=2, got=3"},
+		{"let f = fn(a, b = 1) { a }; f(1, c: 2)", "unknown keyword argument to `f`: c"},
+		{"let f = fn(a, b = 1) { a }; f(1, a: 2)", "multiple values for argument a to `f`"},
+		{"let f = fn(a, b = 1) { a }; f(b: 2)", "missing argument a to `f`"},
+		{"let f = fn(a, ...rest) { a }; f(1, rest: 2)", "unknown keyword argument to `f`: rest"},
+		{"let f = fn([a], b = 1) { a }; f(b: 2)", "missing argument [a] to `f`"},
+		{"let f = fn(a) { a }; f(a: 1 / 0)", "division by zero"},
+		{"len(\"abc\", strict: true)", "unknown keyword argument: strict"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok, fmt.Sprintf("for input: %v", test.input))
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
+
+func Test_BuiltinKeywordArguments(t *testing.T) {
+	builtins["join"] = &object.Builtin{
+		Fn: func(kwargs object.Keywords, args ...object.Object) object.Object {
+			separator := ","
+			if value, ok := kwargs["sep"]; ok {
+				separator = value.(*object.String).Value
+			}
+
+			parts := []string{}
+			for _, arg := range args {
+				parts = append(parts, arg.Inspect())
+			}
+
+			return &object.String{Value: strings.Join(parts, separator)}
+		},
+		Keywords: []string{"sep"},
+	}
+	defer delete(builtins, "join")
+
+	evaluated := testEval("join(1, 2, 3)")
+	assert.Equal(t, "1,2,3", evaluated.Inspect())
+
+	evaluated = testEval("join(1, 2, 3, sep: \" - \")")
+	assert.Equal(t, "1 - 2 - 3", evaluated.Inspect())
+
+	evaluated = testEval("join(1, end: \".\")")
+	result, ok := evaluated.(*object.Error)
+	assert.True(t, ok)
+	assert.Equal(t, "unknown keyword argument: end", result.Message)
+}
+
+func Test_MatchExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (1) { 1 => \"one\", _ => \"other\" }", "one"},
+		{"match (2) { 1 => \"one\", _ => \"other\" }", "other"},
+		{"match (2.0) { 2 => \"two\" }", "two"},
+		{"match (-3) { -3 => true }", "true"},
+		{"match (\"b\") { \"a\" => 1, \"b\" => 2 }", "2"},
+		{"match (false) { true => 1, false => 0 }", "0"},
+		{"match (\"1\") { 1 => \"int\", _ => \"str\" }", "str"},
+		{"match (5) { n => n * 2 }", "10"},
+		{"match ([1, 2, 3]) { [] => 0, [x] => x, [x, ...rest] => rest }", "[2, 3]"},
+		{"match ([7]) { [] => 0, [x] => x, [x, ...rest] => rest }", "7"},
+		{"match ([1, 2]) { [1, y] => y }", "2"},
+		{"match ([1, [2, 3]]) { [a, [b, c]] => a + b + c }", "6"},
+		{"match ([1, 2, 3]) { [_, ..._] => \"non-empty\" }", "non-empty"},
+		{"match ({\"kind\": \"circle\", \"r\": 2}) { {kind: \"square\", side} => side, {kind: \"circle\", r} => r * r }", "4"},
+		{"match ({\"a\": 1}) { {b} => 1, _ => 2 }", "2"},
+		{"match (4) { n if n % 2 == 1 => \"odd\", n if n % 2 == 0 => \"even\" }", "even"},
+		{"let x = 1; match (2) { x => x }; x", "1"},
+		{"let f = fn(v) { match (v) { [a, b] if a > b => a, [a, b] => b } }; f([1, 5]) + f([9, 2])", "14"},
+		{"let x = 5; match ([3]) { [y] => x + y }", "8"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_MatchExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (3) { 1 => 1, 2 => 2 }", "no match arm for value: 3"},
+		{"match ([1, 2]) { [a] => a }", "no match arm for value: [1, 2]"},
+		{"match (1 / 0) { _ => 1 }", "division by zero"},
+		{"match (1) { x if x / 0 => 1 }", "division by zero"},
+		{"match (1) { x => y }", "identifier not found: y"},
+		{"match (1) { _ => _ }", "identifier not found: _"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok, fmt.Sprintf("for input: %v", test.input))
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
+
+func Test_ConditionalExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"true ? 1 : 2", "1"},
+		{"false ? 1 : 2", "2"},
+		{"0 ? 1 : 2", "1"},
+		{"let n = 5; n > 3 ? \"big\" : n > 1 ? \"medium\" : \"small\"", "big"},
+		{"let n = 2; n > 3 ? \"big\" : n > 1 ? \"medium\" : \"small\"", "medium"},
+		{"true ? 1 : 1 / 0", "1"},
+		{"false ? 1 / 0 : 2", "2"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_NullishAndOptionalChaining(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let h = {}; h[\"a\"] ?? 5", "5"},
+		{"let h = {\"a\": 1}; h[\"a\"] ?? 5", "1"},
+		{"false ?? 5", "false"},
+		{"0 ?? 5", "0"},
+		{"1 ?? 1 / 0", "1"},
+		{"let h = {}; h[\"a\"]?.[\"b\"]", "null"},
+		{"let h = {\"a\": {\"b\": 2}}; h[\"a\"]?.[\"b\"]", "2"},
+		{"let h = {}; h[\"a\"]?.[\"b\"]?.[\"c\"] ?? \"none\"", "none"},
+		{"let h = {}; h[\"a\"]?.[1 / 0]", "null"},
+		{"let h = {}; h[\"f\"]?.(1)", "null"},
+		{"let h = {\"f\": fn(x) { x * 2 }}; h[\"f\"]?.(4)", "8"},
+		{"let h = {}; let calls = 0; let count = fn() { calls += 1 }; h[\"f\"]?.(count()); calls", "0"},
+		{"[1, 2]?.[1]", "2"},
+		{"let h = {}; h[\"a\"]?.[\"b\"][\"c\"]", "null"},
+		{"let h = {}; h[\"z\"]?.[0][1]", "null"},
+		{"let h = {}; h.a?.b.c", "null"},
+		{"let h = {}; h.a?.b(1)[0:2].c", "null"},
+		{"let h = {}; h.f?.()()", "null"},
+		{"let h = {}; let calls = 0; let count = fn() { calls += 1 }; h.a?.b.c(count())[count()]; calls", "0"},
+		{"let h = {}; 1 |> h.a?.f()", "null"},
+		{"let h = {\"a\": {\"b\": [10, 20]}}; h.a?.b[1]", "20"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_ConditionalOperatorErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(1 / 0) ? 1 : 2", "division by zero"},
+		{"let h = {}; h[\"a\"][\"b\"]", "index operator not supported: NULL"},
+		{"let h = {\"a\": {}}; h[\"a\"]?.[\"b\"][\"c\"]", "index operator not supported: NULL"},
+		{"let h = {}; h[\"a\"]?.[\"b\"] + 1", "type mismatch: NULL + INTEGER"},
+		{"5?.[0]", "index operator not supported: INTEGER"},
+		{"5?.(0)", "not a function: INTEGER"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok, fmt.Sprintf("for input: %v", test.input))
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
+
+func Test_Ranges(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1..5", "1..5"},
+		{"let n = 3; 0..=n * 2", "0..=6"},
+		{"let sum = 0; for (i in 1..5) { sum += i; } sum", "10"},
+		{"let sum = 0; for (i in 1..=5) { sum += i; } sum", "15"},
+		{"let count = 0; for (i in 5..1) { count += 1; } count", "0"},
+		{"let count = 0; for (i in 3..3) { count += 1; } count", "0"},
+		{"let count = 0; for (i in 3..=3) { count += 1; } count", "1"},
+		{"let last = 0; for (i in -2..0) { last = i; } last", "-1"},
+		{"let seen = []; for (i in 9223372036854775806..=9223372036854775807) { seen = push(seen, i); } seen", "[9223372036854775806, 9223372036854775807]"},
+		{"let f = fn() { for (i in 0..1000000000) { if (i == 3) { return i; } } }; f()", "3"},
+		{"let a = [\"x\", \"y\"]; let out = \"\"; for (i in 0..len(a)) { out += a[i]; } out", "xy"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_Slices(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2, 3, 4][1:3]", "[2, 3]"},
+		{"[1, 2, 3, 4][:2]", "[1, 2]"},
+		{"[1, 2, 3, 4][2:]", "[3, 4]"},
+		{"[1, 2, 3, 4][:]", "[1, 2, 3, 4]"},
+		{"[1, 2, 3, 4][-2:]", "[3, 4]"},
+		{"[1, 2, 3, 4][:-1]", "[1, 2, 3]"},
+		{"[1, 2, 3, 4][-10:10]", "[1, 2, 3, 4]"},
+		{"[1, 2, 3, 4][3:1]", "[]"},
+		{"[1, 2, 3][5:]", "[]"},
+		{"[1, 2][0:99999999999999999999]", "[1, 2]"},
+		{"let a = [1, 2, 3]; let b = a[:]; b[0] = 9; a", "[1, 2, 3]"},
+		{"\"hello\"[1:3]", "el"},
+		{"\"hello\"[:-1]", "hell"},
+		{"\"héllo\"[1:2]", "é"},
+		{"\"abc\"[-2:]", "bc"},
+		{"let h = {}; h.a?.[1:]", "null"},
+		{"let a = [1, 2, 3]; a[-1] = 9; a", "[1, 2, 9]"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_RangeAndSliceErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1..\"a\"", "range bounds must be INTEGER, got STRING"},
+		{"1.5..3", "range bounds must be INTEGER, got FLOAT"},
+		{"0..(2 ** 64)", "range bounds too large: 0..18446744073709551616"},
+		{"[1, 2][\"a\":]", "slice index must be INTEGER, got STRING"},
+		{"[1, 2][:1.5]", "slice index must be INTEGER, got FLOAT"},
+		{"{}[1:2]", "slice operator not supported: HASH"},
+		{"5[1:2]", "slice operator not supported: INTEGER"},
+		{"[1][1 / 0:]", "division by zero"},
+		{"(0..3)[1]", "index operator not supported: RANGE"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok, fmt.Sprintf("for input: %v", test.input))
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
+
+func Test_PipeOperator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2] |> push(3)", "[1, 2, 3]"},
+		{"\"abc\" |> len", "3"},
+		{"let double = fn(x) { x * 2 }; let add = fn(x, y) { x + y }; 5 |> double |> add(1)", "11"},
+		{"let sub = fn(a, b) { a - b }; 10 |> sub(3)", "7"},
+		{"1..4 |> fn(r) { r }", "1..4"},
+		{"let h = {\"inc\": fn(x, by = 1) { x + by }}; 1 |> h.inc(by: 5)", "6"},
+		{"\"a,b\" |> \"x\".contains", "false"},
+		{"let h = {}; 1 |> h.f?.()", "null"},
+		{"let x = 2 |> fn(n) { n * n }; x", "4"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_PipeOperatorErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		line     int
+		column   int
+	}{
+		{"1 |> 5", "cannot pipe into INTEGER: not a function", 1, 6},
+		{"let h = {\"a\": 1}; 1 |> h.a(2)", "cannot pipe into INTEGER: not a function", 1, 24},
+		{"1 |> [1].missing()", "member not found: ARRAY.missing", 1, 6},
+		{"1 |> fn() { 1 }", "wrong number of arguments to anonymous function: want=0, got=1", 1, 1},
+		{"undefined |> len", "identifier not found: undefined", 1, 1},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok, fmt.Sprintf("for input: %v", test.input))
+
+		assert.Equal(t, test.expected, result.Message)
+		assert.Equal(t, test.line, result.Pos.Line, fmt.Sprintf("for input: %v", test.input))
+		assert.Equal(t, test.column, result.Pos.Column, fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_ArrowFunctions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let double = x => x * 2; double(4)", "8"},
+		{"let add = (a, b) => a + b; add(2, 3)", "5"},
+		{"let f = () => { let x = 1; x + 1 }; f()", "2"},
+		{"let add = x => y => x + y; add(1)(2)", "3"},
+		{"let f = (a, b = 10, ...rest) => [a, b, rest]; f(1)", "[1, 10, []]"},
+		{"let f = ([a, b]) => a * b; f([3, 4])", "12"},
+		{"[1, 2, 3] |> (xs => push(xs, 4))", "[1, 2, 3, 4]"},
+		{"let apply = fn(f, x) { f(x) }; apply(x => x + 1, 1)", "2"},
+		{"let f = (n) => { if (n > 0) { return \"pos\"; } \"neg\" }; f(1) + f(-1)", "posneg"},
+		{"let total = 10; let f = x => x + total; f(1)", "11"},
+		{"match (5) { n if (n > 3) => \"big\", _ => \"small\" }", "big"},
+		{"let f = x => x; f(f)(7)", "7"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_ArrowFunctionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let double = x => x * 2; double()", "wrong number of arguments to `double`: want=1, got=0"},
+		{"let f = (a, b) => a; f(1, 2, 3)", "wrong number of arguments to `f`: want=2, got=3"},
+		{"let f = x => x.missing; f(1)", "member not found: INTEGER.missing"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok, fmt.Sprintf("for input: %v", test.input))
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
+
+func Test_TryCatch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { 1 } catch (e) { 2 }", "1"},
+		{"try { throw \"bad\"; 1 } catch (e) { e.message }", "bad"},
+		{"try { throw \"bad\" } catch (e) { e.kind }", "Error"},
+		{"try { 1 / 0 } catch (e) { e.message }", "division by zero"},
+		{"try { 1 / 0 } catch (e) { e.kind }", "RuntimeError"},
+		{"try { len(1) } catch (e) { e.message }", "argument to `len` not supported, got INTEGER"},
+		{"try { missing } catch (e) { e[\"message\"] }", "identifier not found: missing"},
+		{"try { throw {\"message\": \"no id\", \"kind\": \"ValueError\", \"record\": 7} } catch (e) { [e.kind, e.message, e.record] }", "[ValueError, no id, 7]"},
+		{"try { throw {\"message\": \"x\"} } catch ({kind}) { kind }", "Error"},
+		{"let inner = fn() { throw \"deep\" }; let outer = fn() { inner() }; try { outer() } catch (e) { e.stack }", "[inner, outer]"},
+		{"try { (fn() { 1 / 0 })() } catch (e) { e.stack }", "[anonymous function]"},
+		{"try { throw \"x\" } catch (e) { e.stack }", "[]"},
+		{"let log = []; try { log = push(log, 1); } finally { log = push(log, 2); } log", "[1, 2]"},
+		{"let log = []; try { throw \"x\" } catch (e) { log = push(log, e.message); } finally { log = push(log, \"done\"); } log", "[x, done]"},
+		{"let f = fn() { try { return 1; } finally { 2 } }; f()", "1"},
+		{"let f = fn() { try { return 1; } finally { return 2; } }; f()", "2"},
+		{"try { throw \"x\" } finally { 1 }", "ERROR: 1:7: x"},
+		{"try { throw \"x\" } catch (e) { throw \"y\" } finally { 1 }", "ERROR: 1:31: y"},
+		{"try { throw \"x\" } catch (e) { try { throw e } catch (again) { again.message } }", "x"},
+		{"let f = fn() { try { throw \"x\" } finally { return 5; } }; f()", "5"},
+		{"let count = 0; for (i in [1, 2, 3]) { try { if (i == 2) { throw \"skip\" } count += i; } catch (e) { continue; } } count", "4"},
+		{"let n = 0; while (true) { try { break; } finally { n = 1; } } n", "1"},
+		{"let parse = fn(r) { if (r < 0) { throw \"bad record\" } r }; let out = []; for (r in [1, -1, 2]) { try { out = push(out, parse(r)); } catch (e) {} } out", "[1, 2]"},
+		{"let x = try { throw \"x\" } catch (e) { 0 }; x", "0"},
+		{"try { 1 } catch (e) {}", "1"},
+		{"try { throw \"x\" } catch (e) {}", "null"},
+		{"try {} finally {}", "null"},
+		{"try { throw \"x\" } catch (e) { e }; e", "ERROR: 1:36: identifier not found: e"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_ThrowErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		kind     string
+	}{
+		{"throw \"bad record\"", "bad record", "Error"},
+		{"throw {\"message\": \"bad\", \"kind\": \"ValueError\"}", "bad", "ValueError"},
+		{"throw 1", "cannot throw INTEGER, want STRING or HASH", "RuntimeError"},
+		{"throw {}", "thrown hash has no message", "RuntimeError"},
+		{"throw {\"message\": 1}", "thrown message must be STRING, got INTEGER", "RuntimeError"},
+		{"throw {\"message\": \"m\", \"kind\": true}", "thrown kind must be STRING, got BOOLEAN", "RuntimeError"},
+		{"throw missing", "identifier not found: missing", "RuntimeError"},
+		{"try { throw \"x\" } catch ([a]) { a }", "cannot destructure HASH as array", "RuntimeError"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok, fmt.Sprintf("for input: %v", test.input))
+
+		assert.Equal(t, test.expected, result.Message)
+		assert.Equal(t, test.kind, result.Kind)
+	}
+}
+
+func Test_ErrorStackDepth(t *testing.T) {
+	evaluated := testEval("let f = fn(n) { if (n == 0) { throw \"bottom\" } f(n - 1) }; try { f(500) } catch (e) { len(e.stack) }")
+
+	assert.Equal(t, "100", evaluated.Inspect())
+}
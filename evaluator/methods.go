@@ -0,0 +1,212 @@
+package evaluator
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Jamess-Lucass/interpreter-go/ast"
+	"github.com/Jamess-Lucass/interpreter-go/object"
+)
+
+// method is a function of a built-in type, called through member access on
+// a value of that type as in "abc".upper().
+type method struct {
+	arity int
+	fn    func(receiver object.Object, args ...object.Object) object.Object
+}
+
+var methods = map[object.ObjectType]map[string]method{
+	object.STRING_OBJ: {
+		"len": {
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				return &object.Integer{Value: int64(utf8.RuneCountInString(receiver.(*object.String).Value))}
+			},
+		},
+		"upper": {
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				return &object.String{Value: strings.ToUpper(receiver.(*object.String).Value)}
+			},
+		},
+		"lower": {
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				return &object.String{Value: strings.ToLower(receiver.(*object.String).Value)}
+			},
+		},
+		"trim": {
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				return &object.String{Value: strings.TrimSpace(receiver.(*object.String).Value)}
+			},
+		},
+		"split": {
+			arity: 1,
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				separator, ok := args[0].(*object.String)
+				if !ok {
+					return newError("argument to `split` must be STRING, got %s", args[0].Type())
+				}
+
+				parts := strings.Split(receiver.(*object.String).Value, separator.Value)
+
+				elements := make([]object.Object, len(parts))
+				for i, part := range parts {
+					elements[i] = &object.String{Value: part}
+				}
+
+				return &object.Array{Elements: elements}
+			},
+		},
+		"contains": {
+			arity: 1,
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				substring, ok := args[0].(*object.String)
+				if !ok {
+					return newError("argument to `contains` must be STRING, got %s", args[0].Type())
+				}
+
+				return nativeBoolToBooleanObject(strings.Contains(receiver.(*object.String).Value, substring.Value))
+			},
+		},
+	},
+	object.ARRAY_OBJ: {
+		"len": {
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				return &object.Integer{Value: int64(len(receiver.(*object.Array).Elements))}
+			},
+		},
+		"first": {
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				return builtins["first"].Fn(nil, receiver)
+			},
+		},
+		"last": {
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				return builtins["last"].Fn(nil, receiver)
+			},
+		},
+		"rest": {
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				return builtins["rest"].Fn(nil, receiver)
+			},
+		},
+		"push": {
+			arity: 1,
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				return builtins["push"].Fn(nil, receiver, args[0])
+			},
+		},
+		"contains": {
+			arity: 1,
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				for _, element := range receiver.(*object.Array).Elements {
+					if evalInfixExpression(element, "==", args[0]) == TRUE {
+						return TRUE
+					}
+				}
+
+				return FALSE
+			},
+		},
+		"join": {
+			arity: 1,
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				separator, ok := args[0].(*object.String)
+				if !ok {
+					return newError("argument to `join` must be STRING, got %s", args[0].Type())
+				}
+
+				parts := []string{}
+				for _, element := range receiver.(*object.Array).Elements {
+					parts = append(parts, element.Inspect())
+				}
+
+				return &object.String{Value: strings.Join(parts, separator.Value)}
+			},
+		},
+	},
+	object.HASH_OBJ: {
+		"len": {
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				return &object.Integer{Value: int64(len(receiver.(*object.Hash).Pairs))}
+			},
+		},
+		"keys": {
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				keys := []object.Object{}
+				for _, pair := range receiver.(*object.Hash).SortedPairs() {
+					keys = append(keys, pair.Key)
+				}
+
+				return &object.Array{Elements: keys}
+			},
+		},
+		"values": {
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				values := []object.Object{}
+				for _, pair := range receiver.(*object.Hash).SortedPairs() {
+					values = append(values, pair.Value)
+				}
+
+				return &object.Array{Elements: values}
+			},
+		},
+		"has": {
+			arity: 1,
+			fn: func(receiver object.Object, args ...object.Object) object.Object {
+				key, ok := args[0].(object.Hashable)
+				if !ok {
+					return newError("unusable as hash key: %s", args[0].Type())
+				}
+
+				_, ok = receiver.(*object.Hash).Pairs[key.HashKey()]
+
+				return nativeBoolToBooleanObject(ok)
+			},
+		},
+	},
+}
+
+// evalMemberExpression evaluates obj.name. For hashes this is the value under
+// the string key name, falling back to the hash methods when there is no such
+// key; for other types it is a method of the type bound to obj. It also
+// reports whether an optional chain it belongs to was skipped, as described
+// at evalChainOperand.
+func evalMemberExpression(node *ast.MemberExpression, env *object.Environment) (object.Object, bool) {
+	left, skipped := evalChainOperand(node.Object, node.Optional, env)
+	if skipped || isUnwinding(left) {
+		return left, skipped
+	}
+
+	name := node.Property.Value
+
+	if hash, ok := left.(*object.Hash); ok {
+		key := &object.String{Value: name}
+		if pair, ok := hash.Pairs[key.HashKey()]; ok {
+			return pair.Value, false
+		}
+	}
+
+	m, ok := methods[left.Type()][name]
+	if !ok {
+		if left.Type() == object.HASH_OBJ {
+			return NULL, false
+		}
+
+		return newError("member not found: %s.%s", left.Type(), name), false
+	}
+
+	return bindMethod(left, name, m), false
+}
+
+// bindMethod returns m as a builtin with receiver bound, so that it can be
+// called or passed around like any other function.
+func bindMethod(receiver object.Object, name string, m method) *object.Builtin {
+	return &object.Builtin{
+		Fn: func(_ object.Keywords, args ...object.Object) object.Object {
+			if len(args) != m.arity {
+				return newError("wrong number of arguments to %s.%s: want=%d, got=%d", receiver.Type(), name, m.arity, len(args))
+			}
+
+			return m.fn(receiver, args...)
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package evaluator
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Jamess-Lucass/interpreter-go/object"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_HashMemberAccess(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let h = {"name": "Ann"}; h.name`, "Ann"},
+		{`let h = {"a": {"b": 2}}; h.a.b`, "2"},
+		{`let h = {}; h.missing`, "null"},
+		{`let h = {}; h?.a?.b`, "null"},
+		{`let h = {"a": {}}; h.a?.b?.c ?? "none"`, "none"},
+		{`let h = {"double": fn(x) { x * 2 }}; h.double(4)`, "8"},
+		{`let h = {"len": 10}; h.len`, "10"},
+		{`let h = {"n": 1}; h.n = 5; h.n += 2; h["n"]`, "7"},
+		{`let h = {}; h.fresh = true; h.fresh`, "true"},
+		{`let h = {"name": "Ann"}; h.name.upper()`, "ANN"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_BuiltinTypeMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"héllo".len()`, "5"},
+		{`"abc".upper()`, "ABC"},
+		{`"ABC".lower()`, "abc"},
+		{`"  x  ".trim()`, "x"},
+		{`"a,b,c".split(",")`, "[a, b, c]"},
+		{`"abc".contains("bc")`, "true"},
+		{`[1, 2, 3].len()`, "3"},
+		{`[1, 2, 3].first()`, "1"},
+		{`[1, 2, 3].last()`, "3"},
+		{`[1, 2, 3].rest()`, "[2, 3]"},
+		{`[].first()`, "null"},
+		{`let a = [1]; a.push(2); a`, "[1]"},
+		{`[1, 2].push(3)`, "[1, 2, 3]"},
+		{`[1, 2.0, "x"].contains(2)`, "true"},
+		{`[1, 2].contains("1")`, "false"},
+		{`[1, "b", 3].join("-")`, "1-b-3"},
+		{`{"b": 2, "a": 1}.keys()`, "[a, b]"},
+		{`{"b": 2, "a": 1}.values()`, "[1, 2]"},
+		{`{"a": 1}.has("a")`, "true"},
+		{`{"a": 1}.len()`, "1"},
+		{`let upper = "abc".upper; upper()`, "ABC"},
+		{`"a b".split(" ").join("+").upper()`, "A+B"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		assert.Equal(t, test.expected, evaluated.Inspect(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
+func Test_MemberErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`5.len()`, "member not found: INTEGER.len"},
+		{`"abc".reverse()`, "member not found: STRING.reverse"},
+		{`let h = {}; h.missing()`, "not a function: NULL"},
+		{`let h = {}; h.a.b`, "member not found: NULL.b"},
+		{`"abc".len(1)`, "wrong number of arguments to STRING.len: want=0, got=1"},
+		{`"abc".split()`, "wrong number of arguments to STRING.split: want=1, got=0"},
+		{`"abc".split(1)`, "argument to `split` must be STRING, got INTEGER"},
+		{`[1].join(",", sep: 1)`, "unknown keyword argument: sep"},
+		{`{}.has([1])`, "unusable as hash key: ARRAY"},
+		{`let a = [1]; a.x = 1`, "member assignment not supported: ARRAY"},
+		{`let h = freeze({"a": 1}); h.a = 2`, "cannot modify frozen HASH"},
+	}
+
+	for _, test := range tests {
+		evaluated := testEval(test.input)
+		result, ok := evaluated.(*object.Error)
+		assert.True(t, ok, fmt.Sprintf("for input: %v", test.input))
+
+		assert.Equal(t, test.expected, result.Message)
+	}
+}
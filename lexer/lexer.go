@@ -1,56 +1,175 @@
 package lexer
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
 	"github.com/Jamess-Lucass/interpreter-go/token"
 )
 
 type Lexer struct {
 	input        string
+	filename     string
 	position     int
 	readPosition int
-	character    byte
+	character    rune
+
+	// line and column of the current character
+	line   int
+	column int
+
+	errors []string
+
+	keepComments bool
+	comments     []token.Comment
+}
+
+// Option configures optional behaviour of a Lexer.
+type Option func(*Lexer)
+
+// WithFilename sets the file name reported in token positions.
+func WithFilename(filename string) Option {
+	return func(l *Lexer) {
+		l.filename = filename
+	}
+}
+
+// WithComments retains comments, attaching them to the token that follows.
+func WithComments() Option {
+	return func(l *Lexer) {
+		l.keepComments = true
+	}
 }
 
-func NewLexer(input string) *Lexer {
-	l := &Lexer{input: input}
+func NewLexer(input string, options ...Option) *Lexer {
+	l := &Lexer{input: input, line: 1}
+
+	for _, option := range options {
+		option(l)
+	}
+
 	l.readCharacter()
 
 	return l
 }
 
+// Errors returns the diagnostics reported while scanning, such as
+// unterminated strings or invalid escape sequences.
+func (l *Lexer) Errors() []string {
+	return l.errors
+}
+
+// Clone returns a lexer that continues scanning from the same position, so
+// that tokens can be looked ahead at without consuming them. Diagnostics
+// reported by the clone are not seen by the original.
+func (l *Lexer) Clone() *Lexer {
+	clone := *l
+	clone.errors = nil
+	clone.comments = nil
+
+	return &clone
+}
+
+// errorf records a diagnostic prefixed with the position it occurred at.
+func (l *Lexer) errorf(pos token.Position, format string, a ...interface{}) {
+	msg := fmt.Sprintf(format, a...)
+
+	l.errors = append(l.errors, fmt.Sprintf("%s: %s", pos, msg))
+}
+
 func (l *Lexer) readCharacter() {
+	if l.character == '\n' {
+		l.line++
+		l.column = 0
+	}
+
+	width := 0
 	if l.readPosition >= len(l.input) {
 		l.character = 0 // ASCII code for "NUL"
 	} else {
-		l.character = l.input[l.readPosition]
+		l.character, width = utf8.DecodeRuneInString(l.input[l.readPosition:])
 	}
 
 	l.position = l.readPosition
-	l.readPosition++
+	l.readPosition += width
+	l.column++
+}
+
+// currentPosition returns the position of the current character.
+func (l *Lexer) currentPosition() token.Position {
+	return token.Position{
+		Filename: l.filename,
+		Offset:   l.position,
+		Line:     l.line,
+		Column:   l.column,
+	}
 }
 
 func (l *Lexer) NextToken() token.Token {
-	var tok token.Token
+	l.skipTrivia()
+
+	start := l.currentPosition()
+	tok := l.scanToken()
+	tok.Pos = start
+	tok.End = l.currentPosition()
+	tok.Comments = l.comments
+
+	l.comments = nil
+
+	if tok.Type == token.EOF {
+		tok.End = start
+	}
+
+	return tok
+}
 
-	l.skipWhitespace()
+func (l *Lexer) scanToken() token.Token {
+	var tok token.Token
 
 	switch l.character {
 	case '=':
 		if l.peekCharacter() == '=' {
-			character := l.character
-			l.readCharacter()
-			tok = token.Token{Type: token.EQ, Literal: string(character) + string(l.character)}
+			tok = l.newTwoCharacterToken(token.EQ)
+		} else if l.peekCharacter() == '>' {
+			tok = l.newTwoCharacterToken(token.ARROW)
 		} else {
 			tok = token.NewToken(token.ASSIGN, l.character)
 		}
 	case '!':
 		if l.peekCharacter() == '=' {
-			character := l.character
-			l.readCharacter()
-			tok = token.Token{Type: token.NOT_EQ, Literal: string(character) + string(l.character)}
+			tok = l.newTwoCharacterToken(token.NOT_EQ)
 		} else {
 			tok = token.NewToken(token.BANG, l.character)
 		}
+	case '&':
+		if l.peekCharacter() == '&' {
+			tok = l.newTwoCharacterToken(token.AND)
+		} else {
+			tok = token.NewToken(token.AMPERSAND, l.character)
+		}
+	case '|':
+		if l.peekCharacter() == '|' {
+			tok = l.newTwoCharacterToken(token.OR)
+		} else if l.peekCharacter() == '>' {
+			tok = l.newTwoCharacterToken(token.PIPE)
+		} else {
+			tok = token.NewToken(token.BAR, l.character)
+		}
+	case '?':
+		if l.peekCharacter() == '?' {
+			tok = l.newTwoCharacterToken(token.NULLISH)
+		} else if l.peekCharacter() == '.' {
+			tok = l.newTwoCharacterToken(token.OPTIONAL_CHAIN)
+		} else {
+			tok = token.NewToken(token.QUESTION, l.character)
+		}
+	case '^':
+		tok = token.NewToken(token.CARET, l.character)
+	case '~':
+		tok = token.NewToken(token.TILDE, l.character)
 	case ';':
 		tok = token.NewToken(token.SEMICOLON, l.character)
 	case '(':
@@ -60,41 +179,89 @@ func (l *Lexer) NextToken() token.Token {
 	case ',':
 		tok = token.NewToken(token.COMMA, l.character)
 	case '+':
-		tok = token.NewToken(token.PLUS, l.character)
+		if l.peekCharacter() == '=' {
+			tok = l.newTwoCharacterToken(token.PLUS_ASSIGN)
+		} else {
+			tok = token.NewToken(token.PLUS, l.character)
+		}
 	case '{':
 		tok = token.NewToken(token.LBRACE, l.character)
 	case '}':
 		tok = token.NewToken(token.RBRACE, l.character)
 	case '-':
-		tok = token.NewToken(token.MINUS, l.character)
+		if l.peekCharacter() == '=' {
+			tok = l.newTwoCharacterToken(token.MINUS_ASSIGN)
+		} else {
+			tok = token.NewToken(token.MINUS, l.character)
+		}
 	case '/':
-		tok = token.NewToken(token.SLASH, l.character)
+		if l.peekCharacter() == '=' {
+			tok = l.newTwoCharacterToken(token.SLASH_ASSIGN)
+		} else {
+			tok = token.NewToken(token.SLASH, l.character)
+		}
 	case '*':
-		tok = token.NewToken(token.ASTERISK, l.character)
+		if l.peekCharacter() == '*' {
+			tok = l.newTwoCharacterToken(token.POWER)
+		} else if l.peekCharacter() == '=' {
+			tok = l.newTwoCharacterToken(token.ASTERISK_ASSIGN)
+		} else {
+			tok = token.NewToken(token.ASTERISK, l.character)
+		}
+	case '%':
+		tok = token.NewToken(token.PERCENT, l.character)
 	case '<':
-		tok = token.NewToken(token.LT, l.character)
+		if l.peekCharacter() == '=' {
+			tok = l.newTwoCharacterToken(token.LT_EQ)
+		} else if l.peekCharacter() == '<' {
+			tok = l.newTwoCharacterToken(token.SHIFT_LEFT)
+		} else {
+			tok = token.NewToken(token.LT, l.character)
+		}
 	case '>':
-		tok = token.NewToken(token.GT, l.character)
+		if l.peekCharacter() == '=' {
+			tok = l.newTwoCharacterToken(token.GT_EQ)
+		} else if l.peekCharacter() == '>' {
+			tok = l.newTwoCharacterToken(token.SHIFT_RIGHT)
+		} else {
+			tok = token.NewToken(token.GT, l.character)
+		}
 	case '[':
 		tok = token.NewToken(token.LBRACKET, l.character)
 	case ']':
 		tok = token.NewToken(token.RBRACKET, l.character)
 	case ':':
 		tok = token.NewToken(token.COLON, l.character)
+	case '.':
+		if strings.HasPrefix(l.input[l.position:], "...") {
+			l.readCharacter()
+			l.readCharacter()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: "..."}
+		} else if strings.HasPrefix(l.input[l.position:], "..=") {
+			l.readCharacter()
+			l.readCharacter()
+			tok = token.Token{Type: token.RANGE_INCLUSIVE, Literal: "..="}
+		} else if l.peekCharacter() == '.' {
+			tok = l.newTwoCharacterToken(token.RANGE)
+		} else {
+			tok = token.NewToken(token.DOT, l.character)
+		}
 	case 0:
 		tok.Literal = ""
 		tok.Type = token.EOF
 	case '"':
 		tok.Type = token.STRING
 		tok.Literal = l.readString()
+	case '`':
+		tok.Type = token.STRING
+		tok.Literal = l.readRawString()
 	default:
 		if isLetter(l.character) {
 			tok.Literal = l.readIdentifier()
 			tok.Type = token.LookupIdent(tok.Literal)
 			return tok
 		} else if isDigit(l.character) {
-			tok.Literal = l.readNumber()
-			tok.Type = token.INT
+			tok.Literal, tok.Type = l.readNumber()
 			return tok
 		} else {
 			tok = token.NewToken(token.ILLEGAL, l.character)
@@ -106,36 +273,255 @@ func (l *Lexer) NextToken() token.Token {
 	return tok
 }
 
+// newTwoCharacterToken consumes the next character and returns a token made
+// up of the current and next characters.
+func (l *Lexer) newTwoCharacterToken(tokenType token.TokenType) token.Token {
+	character := l.character
+	l.readCharacter()
+
+	return token.Token{Type: tokenType, Literal: string(character) + string(l.character)}
+}
+
+// skipTrivia skips whitespace and comments ahead of the next token.
+func (l *Lexer) skipTrivia() {
+	for {
+		l.skipWhitespace()
+
+		if l.character != '/' {
+			return
+		}
+
+		switch l.peekCharacter() {
+		case '/':
+			l.readLineComment()
+		case '*':
+			l.readBlockComment()
+		default:
+			return
+		}
+	}
+}
+
 func (l *Lexer) skipWhitespace() {
 	for l.character == ' ' || l.character == '\t' || l.character == '\n' || l.character == '\r' {
 		l.readCharacter()
 	}
 }
 
+func (l *Lexer) readLineComment() {
+	start := l.currentPosition()
+
+	for l.character != '\n' && l.character != 0 {
+		l.readCharacter()
+	}
+
+	l.addComment(start)
+}
+
+func (l *Lexer) readBlockComment() {
+	start := l.currentPosition()
+
+	// skip the opening "/*"
+	l.readCharacter()
+	l.readCharacter()
+
+	for !(l.character == '*' && l.peekCharacter() == '/') {
+		if l.character == 0 {
+			l.errorf(start, "unterminated block comment")
+			l.addComment(start)
+			return
+		}
+
+		l.readCharacter()
+	}
+
+	// skip the closing "*/"
+	l.readCharacter()
+	l.readCharacter()
+
+	l.addComment(start)
+}
+
+func (l *Lexer) addComment(start token.Position) {
+	if !l.keepComments {
+		return
+	}
+
+	end := l.currentPosition()
+
+	l.comments = append(l.comments, token.Comment{
+		Text: l.input[start.Offset:end.Offset],
+		Pos:  start,
+		End:  end,
+	})
+}
+
 func (l *Lexer) readIdentifier() string {
 	currentPosition := l.position
-	for isLetter(l.character) {
+	for isLetter(l.character) || unicode.IsMark(l.character) {
 		l.readCharacter()
 	}
 
 	return l.input[currentPosition:l.position]
 }
 
-func (l *Lexer) readNumber() string {
+// readNumber reads an integer or a float literal. Integers may use a 0x, 0o
+// or 0b prefix, and digits may be separated by underscores. A float has a
+// fractional part, an exponent or both, e.g. 3.14, 1e-9 or 2.5E3.
+func (l *Lexer) readNumber() (string, token.TokenType) {
 	currentPosition := l.position
-	for isDigit(l.character) {
+	tokenType := token.TokenType(token.INT)
+
+	if l.character == '0' && isBasePrefix(l.peekCharacter()) {
+		l.readCharacter()
+		l.readCharacter()
+
+		// Read letters as well as digits so that invalid digits such as
+		// 0b102 end up in the literal and are reported by the parser.
+		for isLetter(l.character) || isDigit(l.character) {
+			l.readCharacter()
+		}
+
+		return l.input[currentPosition:l.position], tokenType
+	}
+
+	l.readDigits()
+
+	if l.character == '.' && isDigit(l.peekCharacter()) {
+		tokenType = token.FLOAT
 		l.readCharacter()
+		l.readDigits()
 	}
 
-	return l.input[currentPosition:l.position]
+	if (l.character == 'e' || l.character == 'E') && l.isExponentAhead() {
+		tokenType = token.FLOAT
+		l.readCharacter()
+
+		if l.character == '+' || l.character == '-' {
+			l.readCharacter()
+		}
+
+		l.readDigits()
+	}
+
+	return l.input[currentPosition:l.position], tokenType
 }
 
+func (l *Lexer) readDigits() {
+	for isDigit(l.character) || l.character == '_' {
+		l.readCharacter()
+	}
+}
+
+// isExponentAhead reports whether the 'e' or 'E' at the current position is
+// followed by an optionally signed digit, making it a float exponent.
+func (l *Lexer) isExponentAhead() bool {
+	rest := l.input[l.readPosition:]
+
+	if len(rest) > 0 && (rest[0] == '+' || rest[0] == '-') {
+		rest = rest[1:]
+	}
+
+	return len(rest) > 0 && isDigit(rune(rest[0]))
+}
+
+// readString reads a double quoted string, decoding escape sequences. The
+// string may span multiple lines.
 func (l *Lexer) readString() string {
+	start := l.currentPosition()
+
+	var out strings.Builder
+
+	for {
+		l.readCharacter()
+
+		switch l.character {
+		case '"':
+			return out.String()
+		case 0:
+			l.errorf(start, "unterminated string literal")
+			return out.String()
+		case '\\':
+			l.readEscape(&out)
+		default:
+			out.WriteRune(l.character)
+		}
+	}
+}
+
+// readEscape decodes the escape sequence following a backslash into out.
+func (l *Lexer) readEscape(out *strings.Builder) {
+	start := l.currentPosition()
+
+	l.readCharacter()
+
+	switch l.character {
+	case 'n':
+		out.WriteRune('\n')
+	case 't':
+		out.WriteRune('\t')
+	case 'r':
+		out.WriteRune('\r')
+	case '"':
+		out.WriteRune('"')
+	case '\\':
+		out.WriteRune('\\')
+	case 'u':
+		l.readUnicodeEscape(start, out)
+	case 0:
+		// reported as an unterminated string by the caller
+	default:
+		l.errorf(start, "invalid escape sequence \\%c", l.character)
+		out.WriteRune(l.character)
+	}
+}
+
+// readUnicodeEscape decodes a \u{XXXX} escape where XXXX is one to six hex
+// digits naming a valid code point.
+func (l *Lexer) readUnicodeEscape(start token.Position, out *strings.Builder) {
+	if l.peekCharacter() != '{' {
+		l.errorf(start, "invalid unicode escape: expected {")
+		return
+	}
+
+	l.readCharacter()
+
+	digitsPosition := l.readPosition
+	for isHexDigit(l.peekCharacter()) {
+		l.readCharacter()
+	}
+	digits := l.input[digitsPosition:l.readPosition]
+
+	if l.peekCharacter() != '}' {
+		l.errorf(start, "invalid unicode escape: expected }")
+		return
+	}
+
+	l.readCharacter()
+
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil || len(digits) > 6 || !utf8.ValidRune(rune(value)) {
+		l.errorf(start, "invalid unicode escape \\u{%s}", digits)
+		return
+	}
+
+	out.WriteRune(rune(value))
+}
+
+// readRawString reads a backtick quoted string verbatim, without processing
+// escape sequences. The string may span multiple lines.
+func (l *Lexer) readRawString() string {
+	start := l.currentPosition()
 	currentPosition := l.position + 1
 
 	for {
 		l.readCharacter()
-		if l.character == '"' || l.character == 0 {
+		if l.character == '`' {
+			break
+		}
+
+		if l.character == 0 {
+			l.errorf(start, "unterminated raw string literal")
 			break
 		}
 	}
@@ -143,18 +529,28 @@ func (l *Lexer) readString() string {
 	return l.input[currentPosition:l.position]
 }
 
-func isLetter(ch byte) bool {
-	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
+func isLetter(ch rune) bool {
+	return unicode.IsLetter(ch) || ch == '_'
 }
 
-func isDigit(ch byte) bool {
+func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
-func (l *Lexer) peekCharacter() byte {
+func isBasePrefix(ch rune) bool {
+	return ch == 'x' || ch == 'X' || ch == 'o' || ch == 'O' || ch == 'b' || ch == 'B'
+}
+
+func isHexDigit(ch rune) bool {
+	return isDigit(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+}
+
+func (l *Lexer) peekCharacter() rune {
 	if l.readPosition >= len(l.input) {
 		return 0
 	}
 
-	return l.input[l.readPosition]
+	ch, _ := utf8.DecodeRuneInString(l.input[l.readPosition:])
+
+	return ch
 }
@@ -16,7 +16,7 @@ let add = fn(x, y) {
 };
 
 let result = add(five, ten);
-!-/*5;
+!-/ *5;
 5 < 10 > 5;
 
 if (5 < 10) {
@@ -135,3 +135,622 @@ if (5 < 10) {
 		assert.Equal(t, test.expectedLiteral, token.Literal)
 	}
 }
+
+func Test_NextTokenPositions(t *testing.T) {
+	input := `let x = 5;
+  "hi" + x`
+
+	tests := []struct {
+		expectedType token.TokenType
+		expectedPos  token.Position
+		expectedEnd  token.Position
+	}{
+		{token.LET, token.Position{Filename: "main.mk", Offset: 0, Line: 1, Column: 1}, token.Position{Filename: "main.mk", Offset: 3, Line: 1, Column: 4}},
+		{token.IDENT, token.Position{Filename: "main.mk", Offset: 4, Line: 1, Column: 5}, token.Position{Filename: "main.mk", Offset: 5, Line: 1, Column: 6}},
+		{token.ASSIGN, token.Position{Filename: "main.mk", Offset: 6, Line: 1, Column: 7}, token.Position{Filename: "main.mk", Offset: 7, Line: 1, Column: 8}},
+		{token.INT, token.Position{Filename: "main.mk", Offset: 8, Line: 1, Column: 9}, token.Position{Filename: "main.mk", Offset: 9, Line: 1, Column: 10}},
+		{token.SEMICOLON, token.Position{Filename: "main.mk", Offset: 9, Line: 1, Column: 10}, token.Position{Filename: "main.mk", Offset: 10, Line: 1, Column: 11}},
+		{token.STRING, token.Position{Filename: "main.mk", Offset: 13, Line: 2, Column: 3}, token.Position{Filename: "main.mk", Offset: 17, Line: 2, Column: 7}},
+		{token.PLUS, token.Position{Filename: "main.mk", Offset: 18, Line: 2, Column: 8}, token.Position{Filename: "main.mk", Offset: 19, Line: 2, Column: 9}},
+		{token.IDENT, token.Position{Filename: "main.mk", Offset: 20, Line: 2, Column: 10}, token.Position{Filename: "main.mk", Offset: 21, Line: 2, Column: 11}},
+		{token.EOF, token.Position{Filename: "main.mk", Offset: 21, Line: 2, Column: 11}, token.Position{Filename: "main.mk", Offset: 21, Line: 2, Column: 11}},
+	}
+
+	lexer := NewLexer(input, WithFilename("main.mk"))
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedPos, token.Pos)
+		assert.Equal(t, test.expectedEnd, token.End)
+	}
+}
+
+func Test_NextTokenUnicode(t *testing.T) {
+	input := `let café = "héllo 👋"; größe + 名前;`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LET, "let"},
+		{token.IDENT, "café"},
+		{token.ASSIGN, "="},
+		{token.STRING, "héllo 👋"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "größe"},
+		{token.PLUS, "+"},
+		{token.IDENT, "名前"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, ""},
+	}
+
+	lexer := NewLexer(input)
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedLiteral, token.Literal)
+	}
+}
+
+func Test_NextTokenUnicodePositions(t *testing.T) {
+	lexer := NewLexer(`"👋" 名前`)
+
+	str := lexer.NextToken()
+	assert.Equal(t, token.Position{Offset: 0, Line: 1, Column: 1}, str.Pos)
+	assert.Equal(t, token.Position{Offset: 6, Line: 1, Column: 4}, str.End)
+
+	ident := lexer.NextToken()
+	assert.Equal(t, token.Position{Offset: 7, Line: 1, Column: 5}, ident.Pos)
+	assert.Equal(t, token.Position{Offset: 13, Line: 1, Column: 7}, ident.End)
+}
+
+func Test_NextTokenStrings(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"a\nb"`, "a\nb"},
+		{`"a\tb"`, "a\tb"},
+		{`"say \"hi\""`, `say "hi"`},
+		{`"back\\slash"`, `back\slash`},
+		{`"\u{48}\u{e9}\u{1F44B}"`, "Hé👋"},
+		{"\"line one\nline two\"", "line one\nline two"},
+		{"`raw \\n \"string\"`", `raw \n "string"`},
+		{"`spans\nlines`", "spans\nlines"},
+	}
+
+	for _, test := range tests {
+		lexer := NewLexer(test.input)
+		tok := lexer.NextToken()
+
+		assert.Equal(t, token.TokenType(token.STRING), tok.Type)
+		assert.Equal(t, test.expected, tok.Literal)
+		assert.Empty(t, lexer.Errors())
+		assert.Equal(t, token.TokenType(token.EOF), lexer.NextToken().Type)
+	}
+}
+
+func Test_NextTokenStringErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`"abc`, "1:1: unterminated string literal"},
+		{"let x = \n`abc", "2:1: unterminated raw string literal"},
+		{`"abc\`, "1:1: unterminated string literal"},
+		{`"a\qb"`, `1:3: invalid escape sequence \q`},
+		{`"\u48"`, "1:2: invalid unicode escape: expected {"},
+		{`"\u{48"`, "1:2: invalid unicode escape: expected }"},
+		{`"\u{}"`, `1:2: invalid unicode escape \u{}`},
+		{`"\u{110000}"`, `1:2: invalid unicode escape \u{110000}`},
+	}
+
+	for _, test := range tests {
+		lexer := NewLexer(test.input)
+
+		for tok := lexer.NextToken(); tok.Type != token.EOF; tok = lexer.NextToken() {
+		}
+
+		assert.NotEmpty(t, lexer.Errors(), test.input)
+		if len(lexer.Errors()) > 0 {
+			assert.Equal(t, test.expected, lexer.Errors()[0])
+		}
+	}
+}
+
+func Test_NextTokenComments(t *testing.T) {
+	input := `// leading comment
+let x = 5; // trailing comment
+/* block
+   comment */ x / 2;
+/**/ x`
+
+	tests := []struct {
+		expectedType     token.TokenType
+		expectedComments []string
+	}{
+		{token.LET, []string{"// leading comment"}},
+		{token.IDENT, nil},
+		{token.ASSIGN, nil},
+		{token.INT, nil},
+		{token.SEMICOLON, nil},
+		{token.IDENT, []string{"// trailing comment", "/* block\n   comment */"}},
+		{token.SLASH, nil},
+		{token.INT, nil},
+		{token.SEMICOLON, nil},
+		{token.IDENT, []string{"/**/"}},
+		{token.EOF, nil},
+	}
+
+	lexer := NewLexer(input, WithComments())
+
+	for _, test := range tests {
+		tok := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, tok.Type)
+
+		var comments []string
+		for _, comment := range tok.Comments {
+			comments = append(comments, comment.Text)
+		}
+		assert.Equal(t, test.expectedComments, comments)
+	}
+
+	assert.Empty(t, lexer.Errors())
+}
+
+func Test_NextTokenCommentsDiscarded(t *testing.T) {
+	lexer := NewLexer("// comment\nx /* comment */ + 1")
+
+	tests := []token.TokenType{token.IDENT, token.PLUS, token.INT, token.EOF}
+
+	for _, expected := range tests {
+		tok := lexer.NextToken()
+
+		assert.Equal(t, expected, tok.Type)
+		assert.Nil(t, tok.Comments)
+	}
+}
+
+func Test_NextTokenCommentPositions(t *testing.T) {
+	lexer := NewLexer("x\n  /* a */ y", WithComments())
+	lexer.NextToken()
+
+	tok := lexer.NextToken()
+
+	assert.Len(t, tok.Comments, 1)
+	assert.Equal(t, token.Position{Offset: 4, Line: 2, Column: 3}, tok.Comments[0].Pos)
+	assert.Equal(t, token.Position{Offset: 11, Line: 2, Column: 10}, tok.Comments[0].End)
+}
+
+func Test_NextTokenUnterminatedBlockComment(t *testing.T) {
+	lexer := NewLexer("x /* never closed")
+
+	assert.Equal(t, token.TokenType(token.IDENT), lexer.NextToken().Type)
+	assert.Equal(t, token.TokenType(token.EOF), lexer.NextToken().Type)
+	assert.Equal(t, []string{"1:3: unterminated block comment"}, lexer.Errors())
+}
+
+func Test_NextTokenNumbers(t *testing.T) {
+	input := `5 3.14 1e-9 2.5E3 6e+2 10.x 1.e 3else`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.INT, "5"},
+		{token.FLOAT, "3.14"},
+		{token.FLOAT, "1e-9"},
+		{token.FLOAT, "2.5E3"},
+		{token.FLOAT, "6e+2"},
+		{token.INT, "10"},
+		{token.DOT, "."},
+		{token.IDENT, "x"},
+		{token.INT, "1"},
+		{token.DOT, "."},
+		{token.IDENT, "e"},
+		{token.INT, "3"},
+		{token.ELSE, "else"},
+		{token.EOF, ""},
+	}
+
+	lexer := NewLexer(input)
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedLiteral, token.Literal)
+	}
+}
+
+func Test_NextTokenIntegerBases(t *testing.T) {
+	input := `0xFF 0o755 0b1010 1_000_000 0XdeadBEEF 1_000.5 0b102`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.INT, "0xFF"},
+		{token.INT, "0o755"},
+		{token.INT, "0b1010"},
+		{token.INT, "1_000_000"},
+		{token.INT, "0XdeadBEEF"},
+		{token.FLOAT, "1_000.5"},
+		{token.INT, "0b102"},
+		{token.EOF, ""},
+	}
+
+	lexer := NewLexer(input)
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedLiteral, token.Literal)
+	}
+}
+
+func Test_NextTokenComparisonAndPowerOperators(t *testing.T) {
+	input := `a <= b >= c ** d * e < f > g`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "a"},
+		{token.LT_EQ, "<="},
+		{token.IDENT, "b"},
+		{token.GT_EQ, ">="},
+		{token.IDENT, "c"},
+		{token.POWER, "**"},
+		{token.IDENT, "d"},
+		{token.ASTERISK, "*"},
+		{token.IDENT, "e"},
+		{token.LT, "<"},
+		{token.IDENT, "f"},
+		{token.GT, ">"},
+		{token.IDENT, "g"},
+		{token.EOF, ""},
+	}
+
+	lexer := NewLexer(input)
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedLiteral, token.Literal)
+	}
+}
+
+func Test_NextTokenModulo(t *testing.T) {
+	input := `10 % 3 % -2;`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.INT, "10"},
+		{token.PERCENT, "%"},
+		{token.INT, "3"},
+		{token.PERCENT, "%"},
+		{token.MINUS, "-"},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, ""},
+	}
+
+	lexer := NewLexer(input)
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedLiteral, token.Literal)
+	}
+}
+
+func Test_NextTokenLogicalOperators(t *testing.T) {
+	input := `a && b || !c`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "a"},
+		{token.AND, "&&"},
+		{token.IDENT, "b"},
+		{token.OR, "||"},
+		{token.BANG, "!"},
+		{token.IDENT, "c"},
+		{token.EOF, ""},
+	}
+
+	lexer := NewLexer(input)
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedLiteral, token.Literal)
+	}
+}
+
+func Test_NextTokenBitwiseOperators(t *testing.T) {
+	input := `a & b | c ^ ~d << 2 >> 1 && e || f`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "a"},
+		{token.AMPERSAND, "&"},
+		{token.IDENT, "b"},
+		{token.BAR, "|"},
+		{token.IDENT, "c"},
+		{token.CARET, "^"},
+		{token.TILDE, "~"},
+		{token.IDENT, "d"},
+		{token.SHIFT_LEFT, "<<"},
+		{token.INT, "2"},
+		{token.SHIFT_RIGHT, ">>"},
+		{token.INT, "1"},
+		{token.AND, "&&"},
+		{token.IDENT, "e"},
+		{token.OR, "||"},
+		{token.IDENT, "f"},
+		{token.EOF, ""},
+	}
+
+	lexer := NewLexer(input)
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedLiteral, token.Literal)
+	}
+}
+
+func Test_NextTokenAssignmentOperators(t *testing.T) {
+	input := `x = 1; x += 2; x -= 3; x *= 4; x /= 5; x **= 6`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "x"},
+		{token.ASSIGN, "="},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.PLUS_ASSIGN, "+="},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.MINUS_ASSIGN, "-="},
+		{token.INT, "3"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.ASTERISK_ASSIGN, "*="},
+		{token.INT, "4"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.SLASH_ASSIGN, "/="},
+		{token.INT, "5"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.POWER, "**"},
+		{token.ASSIGN, "="},
+		{token.INT, "6"},
+		{token.EOF, ""},
+	}
+
+	lexer := NewLexer(input)
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedLiteral, token.Literal)
+	}
+}
+
+func Test_NextTokenEllipsis(t *testing.T) {
+	input := `[a, ...rest] 1.5 .`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.LBRACKET, "["},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "rest"},
+		{token.RBRACKET, "]"},
+		{token.FLOAT, "1.5"},
+		{token.DOT, "."},
+		{token.EOF, ""},
+	}
+
+	lexer := NewLexer(input)
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedLiteral, token.Literal)
+	}
+}
+
+func Test_NextTokenMatch(t *testing.T) {
+	input := `match (x) { 1 => a, _ => b }`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.MATCH, "match"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.INT, "1"},
+		{token.ARROW, "=>"},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.IDENT, "_"},
+		{token.ARROW, "=>"},
+		{token.IDENT, "b"},
+		{token.RBRACE, "}"},
+		{token.EOF, ""},
+	}
+
+	lexer := NewLexer(input)
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedLiteral, token.Literal)
+	}
+}
+
+func Test_NextTokenConditionalOperators(t *testing.T) {
+	input := `a ? b : c ?? d?.[0] ?.(1)`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "a"},
+		{token.QUESTION, "?"},
+		{token.IDENT, "b"},
+		{token.COLON, ":"},
+		{token.IDENT, "c"},
+		{token.NULLISH, "??"},
+		{token.IDENT, "d"},
+		{token.OPTIONAL_CHAIN, "?."},
+		{token.LBRACKET, "["},
+		{token.INT, "0"},
+		{token.RBRACKET, "]"},
+		{token.OPTIONAL_CHAIN, "?."},
+		{token.LPAREN, "("},
+		{token.INT, "1"},
+		{token.RPAREN, ")"},
+		{token.EOF, ""},
+	}
+
+	lexer := NewLexer(input)
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedLiteral, token.Literal)
+	}
+}
+
+func Test_NextTokenPipe(t *testing.T) {
+	input := `a |> f || b | c`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.IDENT, "a"},
+		{token.PIPE, "|>"},
+		{token.IDENT, "f"},
+		{token.OR, "||"},
+		{token.IDENT, "b"},
+		{token.BAR, "|"},
+		{token.IDENT, "c"},
+		{token.EOF, ""},
+	}
+
+	lexer := NewLexer(input)
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedLiteral, token.Literal)
+	}
+}
+
+func Test_NextTokenTryCatch(t *testing.T) {
+	input := `try { throw e; } catch (e) {} finally {}`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.TRY, "try"},
+		{token.LBRACE, "{"},
+		{token.THROW, "throw"},
+		{token.IDENT, "e"},
+		{token.SEMICOLON, ";"},
+		{token.RBRACE, "}"},
+		{token.CATCH, "catch"},
+		{token.LPAREN, "("},
+		{token.IDENT, "e"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.RBRACE, "}"},
+		{token.FINALLY, "finally"},
+		{token.LBRACE, "{"},
+		{token.RBRACE, "}"},
+		{token.EOF, ""},
+	}
+
+	lexer := NewLexer(input)
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedLiteral, token.Literal)
+	}
+}
+
+func Test_NextTokenRanges(t *testing.T) {
+	input := `0..10 1..=n [...a] x.y 1.5..2`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.INT, "0"},
+		{token.RANGE, ".."},
+		{token.INT, "10"},
+		{token.INT, "1"},
+		{token.RANGE_INCLUSIVE, "..="},
+		{token.IDENT, "n"},
+		{token.LBRACKET, "["},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "a"},
+		{token.RBRACKET, "]"},
+		{token.IDENT, "x"},
+		{token.DOT, "."},
+		{token.IDENT, "y"},
+		{token.FLOAT, "1.5"},
+		{token.RANGE, ".."},
+		{token.INT, "2"},
+		{token.EOF, ""},
+	}
+
+	lexer := NewLexer(input)
+
+	for _, test := range tests {
+		token := lexer.NextToken()
+
+		assert.Equal(t, test.expectedType, token.Type)
+		assert.Equal(t, test.expectedLiteral, token.Literal)
+	}
+}
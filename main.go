@@ -5,10 +5,18 @@ import (
 	"os"
 	"os/user"
 
+	"github.com/Jamess-Lucass/interpreter-go/evaluator"
+	"github.com/Jamess-Lucass/interpreter-go/lexer"
+	"github.com/Jamess-Lucass/interpreter-go/object"
+	"github.com/Jamess-Lucass/interpreter-go/parser"
 	"github.com/Jamess-Lucass/interpreter-go/repl"
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		os.Exit(runFile(os.Args[1]))
+	}
+
 	user, err := user.Current()
 	if err != nil {
 		panic(err)
@@ -17,3 +25,32 @@ func main() {
 	fmt.Printf("Hello %s! Please start typing commands\n", user.Username)
 	repl.Start(os.Stdin, os.Stdout)
 }
+
+// runFile evaluates the script at path, returning the process exit code.
+func runFile(path string) int {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	l := lexer.NewLexer(string(source), lexer.WithFilename(path))
+	p := parser.NewParser(l)
+
+	program := p.Parse()
+	if len(p.Errors()) > 0 {
+		for _, msg := range p.Errors() {
+			fmt.Fprintln(os.Stderr, msg)
+		}
+
+		return 1
+	}
+
+	evaluated := evaluator.Eval(program, object.NewEnvironment())
+	if evaluated != nil && evaluated.Type() == object.ERROR_OBJ {
+		fmt.Fprintln(os.Stderr, evaluated.Inspect())
+		return 1
+	}
+
+	return 0
+}
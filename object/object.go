@@ -2,24 +2,34 @@ package object
 
 import (
 	"bytes"
+	"cmp"
 	"fmt"
 	"hash/fnv"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/Jamess-Lucass/interpreter-go/ast"
+	"github.com/Jamess-Lucass/interpreter-go/token"
 )
 
 const (
 	INTEGER_OBJ      = "INTEGER"
+	FLOAT_OBJ        = "FLOAT"
 	STRING_OBJ       = "STRING"
 	BOOLEAN_OBJ      = "BOOLEAN"
 	NULL_OBJ         = "NULL"
 	RETURN_VALUE_OBJ = "RETURN_VALUE"
+	BREAK_OBJ        = "BREAK"
+	CONTINUE_OBJ     = "CONTINUE"
 	ERROR_OBJ        = "ERROR"
 	FUNCTION_OBJ     = "FUNCTION"
 	BUILTIN_OBJ      = "BUILTIN"
 	ARRAY_OBJ        = "ARRAY"
 	HASH_OBJ         = "HASH"
+	RANGE_OBJ        = "RANGE"
 )
 
 type ObjectType string
@@ -40,7 +50,8 @@ type HashPair struct {
 }
 
 type Hash struct {
-	Pairs map[HashKey]HashPair
+	Pairs  map[HashKey]HashPair
+	Frozen bool // set by freeze; frozen hashes reject index assignment
 }
 
 var _ Object = (*Hash)(nil)
@@ -63,27 +74,136 @@ func (h *Hash) Inspect() string {
 	return out.String()
 }
 
+// SortedPairs returns the pairs of the hash ordered by key, giving a stable
+// iteration order. Keys are grouped by type, then ordered by value.
+func (h *Hash) SortedPairs() []HashPair {
+	pairs := make([]HashPair, 0, len(h.Pairs))
+	for _, pair := range h.Pairs {
+		pairs = append(pairs, pair)
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		return compareKeys(pairs[i].Key, pairs[j].Key) < 0
+	})
+
+	return pairs
+}
+
+func compareKeys(a, b Object) int {
+	if a.Type() != b.Type() {
+		return strings.Compare(string(a.Type()), string(b.Type()))
+	}
+
+	switch a := a.(type) {
+	case *Integer:
+		b := b.(*Integer)
+		if !a.IsBig() && !b.IsBig() {
+			return cmp.Compare(a.Value, b.Value)
+		}
+
+		return a.BigValue().Cmp(b.BigValue())
+	case *Float:
+		return cmp.Compare(a.Value, b.(*Float).Value)
+	case *String:
+		return strings.Compare(a.Value, b.(*String).Value)
+	case *Boolean:
+		if a.Value == b.(*Boolean).Value {
+			return 0
+		} else if b.(*Boolean).Value {
+			return -1
+		}
+
+		return 1
+	default:
+		return strings.Compare(a.Inspect(), b.Inspect())
+	}
+}
+
 type Hashable interface {
 	HashKey() HashKey
 }
 
+// Integer is an arbitrary-precision integer. Values that fit in an int64 are
+// held in Value; larger values are held in Big, in which case Value is unused.
 type Integer struct {
 	Value int64
+	Big   *big.Int
 }
 
 var _ Object = (*Integer)(nil)
 var _ Hashable = (*Integer)(nil)
 
+// NewBigInteger returns an Integer for value, only keeping the big
+// representation when value does not fit in an int64.
+func NewBigInteger(value *big.Int) *Integer {
+	if value.IsInt64() {
+		return &Integer{Value: value.Int64()}
+	}
+
+	return &Integer{Big: value}
+}
+
+// IsBig reports whether the integer is too large to be held in an int64.
+func (i *Integer) IsBig() bool {
+	return i.Big != nil
+}
+
+// BigValue returns the integer as a newly allocated big.Int.
+func (i *Integer) BigValue() *big.Int {
+	if i.IsBig() {
+		return new(big.Int).Set(i.Big)
+	}
+
+	return big.NewInt(i.Value)
+}
+
 func (i *Integer) Type() ObjectType {
 	return INTEGER_OBJ
 }
 func (i *Integer) Inspect() string {
+	if i.IsBig() {
+		return i.Big.String()
+	}
+
 	return fmt.Sprintf("%d", i.Value)
 }
 func (i *Integer) HashKey() HashKey {
+	if i.IsBig() {
+		h := fnv.New64a()
+		h.Write([]byte(i.Big.String()))
+
+		return HashKey{Type: i.Type(), Value: h.Sum64()}
+	}
+
 	return HashKey{Type: i.Type(), Value: uint64(i.Value)}
 }
 
+type Float struct {
+	Value float64
+}
+
+var _ Object = (*Float)(nil)
+var _ Hashable = (*Float)(nil)
+
+func (f *Float) Type() ObjectType {
+	return FLOAT_OBJ
+}
+
+// Inspect formats the float so that it reads back as a float literal, always
+// including a fractional part or an exponent.
+func (f *Float) Inspect() string {
+	out := strconv.FormatFloat(f.Value, 'g', -1, 64)
+
+	if math.IsInf(f.Value, 0) || math.IsNaN(f.Value) || strings.ContainsAny(out, ".e") {
+		return out
+	}
+
+	return out + ".0"
+}
+func (f *Float) HashKey() HashKey {
+	return HashKey{Type: f.Type(), Value: math.Float64bits(f.Value)}
+}
+
 type String struct {
 	Value string
 }
@@ -153,8 +273,37 @@ func (n *ReturnValue) Inspect() string {
 	return n.Value.Inspect()
 }
 
+// Break signals a break statement, unwinding to the innermost loop in the
+// same way ReturnValue unwinds to the enclosing function.
+type Break struct{}
+
+var _ Object = (*Break)(nil)
+
+func (b *Break) Type() ObjectType {
+	return BREAK_OBJ
+}
+func (b *Break) Inspect() string {
+	return "break"
+}
+
+// Continue signals a continue statement, unwinding to the innermost loop.
+type Continue struct{}
+
+var _ Object = (*Continue)(nil)
+
+func (c *Continue) Type() ObjectType {
+	return CONTINUE_OBJ
+}
+func (c *Continue) Inspect() string {
+	return "continue"
+}
+
 type Error struct {
 	Message string
+	Kind    string         // the category seen by catch blocks, such as "RuntimeError"
+	Stack   []string       // the functions unwound by the error, innermost first
+	Details *Hash          // the hash given to throw, if any, whose other keys reach catch
+	Pos     token.Position // where the error was raised, if known
 }
 
 var _ Object = (*Error)(nil)
@@ -163,11 +312,16 @@ func (e *Error) Type() ObjectType {
 	return ERROR_OBJ
 }
 func (e *Error) Inspect() string {
+	if e.Pos.IsValid() {
+		return fmt.Sprintf("ERROR: %s: %s", e.Pos, e.Message)
+	}
+
 	return fmt.Sprintf("ERROR: %s", e.Message)
 }
 
 type Function struct {
-	Parameters []*ast.Identifier
+	Name       string // empty for anonymous functions
+	Parameters []*ast.Parameter
 	Body       *ast.BlockStatement
 	Env        *Environment
 }
@@ -195,10 +349,14 @@ func (f *Function) Inspect() string {
 	return out.String()
 }
 
-type BuiltinFunction func(args ...Object) Object
+// Keywords holds the keyword arguments of a call by name.
+type Keywords map[string]Object
+
+type BuiltinFunction func(kwargs Keywords, args ...Object) Object
 
 type Builtin struct {
-	Fn BuiltinFunction
+	Fn       BuiltinFunction
+	Keywords []string // the keyword arguments Fn accepts
 }
 
 var _ Object = (*Builtin)(nil)
@@ -213,6 +371,7 @@ func (b *Builtin) Inspect() string {
 
 type Array struct {
 	Elements []Object
+	Frozen   bool // set by freeze; frozen arrays reject index assignment
 }
 
 var _ Object = (*Array)(nil)
@@ -236,6 +395,28 @@ func (b *Array) Inspect() string {
 	return out.String()
 }
 
+// Range is the sequence of integers from Start up to End, including End only
+// when Inclusive is set.
+type Range struct {
+	Start     int64
+	End       int64
+	Inclusive bool
+}
+
+var _ Object = (*Range)(nil)
+
+func (r *Range) Type() ObjectType {
+	return RANGE_OBJ
+}
+
+func (r *Range) Inspect() string {
+	if r.Inclusive {
+		return fmt.Sprintf("%d..=%d", r.Start, r.End)
+	}
+
+	return fmt.Sprintf("%d..%d", r.Start, r.End)
+}
+
 func NewEnvironment() *Environment {
 	s := make(map[string]Object)
 
@@ -245,6 +426,13 @@ func NewEnvironment() *Environment {
 type Environment struct {
 	store map[string]Object
 	outer *Environment
+
+	// constants holds the names in store that were declared with const.
+	constants map[string]bool
+
+	// depth is the number of function calls active when the environment
+	// was created.
+	depth int
 }
 
 func (e *Environment) Get(name string) (Object, bool) {
@@ -261,9 +449,73 @@ func (e *Environment) Set(name string, value Object) Object {
 	return value
 }
 
+// SetConstant binds name like Set, additionally marking the binding as not
+// reassignable.
+func (e *Environment) SetConstant(name string, value Object) Object {
+	if e.constants == nil {
+		e.constants = make(map[string]bool)
+	}
+
+	e.constants[name] = true
+	e.store[name] = value
+
+	return value
+}
+
+// IsConstant reports whether the nearest enclosing binding of name was
+// declared with const.
+func (e *Environment) IsConstant(name string) bool {
+	if _, ok := e.store[name]; ok {
+		return e.constants[name]
+	}
+
+	if e.outer != nil {
+		return e.outer.IsConstant(name)
+	}
+
+	return false
+}
+
+// IsLocalConstant reports whether name was declared with const in this
+// environment, ignoring enclosing environments.
+func (e *Environment) IsLocalConstant(name string) bool {
+	return e.constants[name]
+}
+
+// Assign updates the nearest enclosing binding of name, reporting whether
+// such a binding exists.
+func (e *Environment) Assign(name string, value Object) bool {
+	if _, ok := e.store[name]; ok {
+		e.store[name] = value
+		return true
+	}
+
+	if e.outer != nil {
+		return e.outer.Assign(name, value)
+	}
+
+	return false
+}
+
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
+	env.depth = outer.depth
 
 	return env
 }
+
+// NewCallEnvironment creates the environment for a function call, enclosed by
+// the function's closure environment and one call deeper than the caller.
+func NewCallEnvironment(closure *Environment, caller *Environment) *Environment {
+	env := NewEnclosedEnvironment(closure)
+	env.depth = caller.depth + 1
+
+	return env
+}
+
+// Depth returns the number of function calls active when the environment was
+// created.
+func (e *Environment) Depth() int {
+	return e.depth
+}
@@ -1,6 +1,7 @@
 package object
 
 import (
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -16,3 +17,16 @@ func Test_StringHashKey(t *testing.T) {
 	assert.Equal(t, hello1.HashKey(), hello2.HashKey())
 	assert.Equal(t, diff1.HashKey(), diff2.HashKey())
 }
+
+func Test_IntegerHashKey(t *testing.T) {
+	small := &Integer{Value: 42}
+	big1 := NewBigInteger(new(big.Int).Lsh(big.NewInt(1), 70))
+	big2 := NewBigInteger(new(big.Int).Lsh(big.NewInt(1), 70))
+	demoted := NewBigInteger(big.NewInt(42))
+
+	assert.True(t, big1.IsBig())
+	assert.False(t, demoted.IsBig())
+	assert.Equal(t, big1.HashKey(), big2.HashKey())
+	assert.Equal(t, small.HashKey(), demoted.HashKey())
+	assert.Equal(t, "1180591620717411303424", big1.Inspect())
+}
@@ -1,7 +1,9 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 	"strconv"
 
 	"github.com/Jamess-Lucass/interpreter-go/ast"
@@ -12,26 +14,61 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN      // x = y
+	PIPE        // x |> f
+	TERNARY     // x ? y : z
+	NULLISH     // ??
+	LOGICAL_OR  // ||
+	LOGICAL_AND // &&
 	EQUALS      // ==
 	LESSGREATER // > or <
+	RANGE       // x..y or x..=y
+	BIT_OR      // |
+	BIT_XOR     // ^
+	BIT_AND     // &
+	SHIFT       // << or >>
 	SUM         // +
 	PRODUCT     // *
 	PREFIX      // -x or !x
+	POWER       // x ** y
 	CALL        // fn(x)
 	INDEX       // array[index]
 )
 
 var precedences = map[token.TokenType]int{
-	token.EQ:       EQUALS,
-	token.NOT_EQ:   EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.SLASH:    PRODUCT,
-	token.ASTERISK: PRODUCT,
-	token.LPAREN:   CALL,
-	token.LBRACKET: INDEX,
+	token.ASSIGN:          ASSIGN,
+	token.PLUS_ASSIGN:     ASSIGN,
+	token.MINUS_ASSIGN:    ASSIGN,
+	token.ASTERISK_ASSIGN: ASSIGN,
+	token.SLASH_ASSIGN:    ASSIGN,
+	token.PIPE:            PIPE,
+	token.QUESTION:        TERNARY,
+	token.NULLISH:         NULLISH,
+	token.OR:              LOGICAL_OR,
+	token.AND:             LOGICAL_AND,
+	token.EQ:              EQUALS,
+	token.NOT_EQ:          EQUALS,
+	token.LT:              LESSGREATER,
+	token.GT:              LESSGREATER,
+	token.LT_EQ:           LESSGREATER,
+	token.GT_EQ:           LESSGREATER,
+	token.RANGE:           RANGE,
+	token.RANGE_INCLUSIVE: RANGE,
+	token.BAR:             BIT_OR,
+	token.CARET:           BIT_XOR,
+	token.AMPERSAND:       BIT_AND,
+	token.SHIFT_LEFT:      SHIFT,
+	token.SHIFT_RIGHT:     SHIFT,
+	token.PLUS:            SUM,
+	token.MINUS:           SUM,
+	token.SLASH:           PRODUCT,
+	token.ASTERISK:        PRODUCT,
+	token.PERCENT:         PRODUCT,
+	token.POWER:           POWER,
+	token.LPAREN:          CALL,
+	token.LBRACKET:        INDEX,
+	token.OPTIONAL_CHAIN:  INDEX,
+	token.DOT:             INDEX,
 }
 
 type (
@@ -49,6 +86,18 @@ type Parser struct {
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
+
+	// loopDepth is the number of loops enclosing the current token within
+	// the current function, used to reject stray break and continue.
+	loopDepth int
+
+	// refutable is set while parsing the pattern of a match arm, where
+	// patterns may contain literals that a value can fail to match.
+	refutable bool
+
+	// guard is set while parsing the guard of a match arm, where => ends the
+	// guard rather than starting an arrow function.
+	guard bool
 }
 
 func (p *Parser) Errors() []string {
@@ -64,8 +113,10 @@ func NewParser(lexer *lexer.Lexer) *Parser {
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
+	p.registerPrefix(token.FLOAT, p.parseFloatLiteral)
 	p.registerPrefix(token.BANG, p.parsePrefixExpression)
 	p.registerPrefix(token.MINUS, p.parsePrefixExpression)
+	p.registerPrefix(token.TILDE, p.parsePrefixExpression)
 	p.registerPrefix(token.TRUE, p.parseBoolean)
 	p.registerPrefix(token.FALSE, p.parseBoolean)
 	p.registerPrefix(token.LPAREN, p.parseGroupedExpression)
@@ -74,16 +125,41 @@ func NewParser(lexer *lexer.Lexer) *Parser {
 	p.registerPrefix(token.STRING, p.parseStringLiteral)
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
 	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
+	p.registerPrefix(token.TRY, p.parseTryExpression)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
 	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
+	p.registerInfix(token.PERCENT, p.parseInfixExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
+	p.registerInfix(token.LT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.GT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.POWER, p.parseInfixExpression)
+	p.registerInfix(token.AMPERSAND, p.parseInfixExpression)
+	p.registerInfix(token.BAR, p.parseInfixExpression)
+	p.registerInfix(token.CARET, p.parseInfixExpression)
+	p.registerInfix(token.SHIFT_LEFT, p.parseInfixExpression)
+	p.registerInfix(token.SHIFT_RIGHT, p.parseInfixExpression)
+	p.registerInfix(token.RANGE, p.parseInfixExpression)
+	p.registerInfix(token.RANGE_INCLUSIVE, p.parseInfixExpression)
+	p.registerInfix(token.ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.PLUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.MINUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.ASTERISK_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.SLASH_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.AND, p.parseLogicalExpression)
+	p.registerInfix(token.OR, p.parseLogicalExpression)
+	p.registerInfix(token.NULLISH, p.parseLogicalExpression)
+	p.registerInfix(token.QUESTION, p.parseConditionalExpression)
+	p.registerInfix(token.PIPE, p.parsePipeExpression)
+	p.registerInfix(token.OPTIONAL_CHAIN, p.parseOptionalChain)
+	p.registerInfix(token.DOT, p.parseMemberExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
 
@@ -108,15 +184,27 @@ func (p *Parser) Parse() *ast.Program {
 		p.NextToken()
 	}
 
+	p.errors = append(p.lexer.Errors(), p.errors...)
+
 	return program
 }
 
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.currentToken.Type {
-	case token.LET:
+	case token.LET, token.CONST:
 		return p.parseLetStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
+	case token.WHILE:
+		return p.parseWhileStatement()
+	case token.FOR:
+		return p.parseForStatement()
+	case token.BREAK:
+		return p.parseBreakStatement()
+	case token.CONTINUE:
+		return p.parseContinueStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -125,11 +213,20 @@ func (p *Parser) parseStatement() ast.Statement {
 func (p *Parser) parseLetStatement() *ast.LetStatement {
 	stmt := &ast.LetStatement{Token: p.currentToken}
 
-	if !p.expectPeek(token.IDENT) {
-		return nil
-	}
+	if p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.LBRACE) {
+		p.NextToken()
 
-	stmt.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+		stmt.Pattern = p.parsePattern()
+		if stmt.Pattern == nil {
+			return nil
+		}
+	} else {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		stmt.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	}
 
 	if !p.expectPeek(token.ASSIGN) {
 		return nil
@@ -139,6 +236,10 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 
 	stmt.Value = p.parseExpression(LOWEST)
 
+	if function, ok := stmt.Value.(*ast.FunctionLiteral); ok && stmt.Name != nil {
+		function.Name = stmt.Name.Value
+	}
+
 	if p.peekTokenIs(token.SEMICOLON) {
 		p.NextToken()
 	}
@@ -160,6 +261,111 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return stmt
 }
 
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{Token: p.currentToken}
+
+	p.NextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseWhileStatement() *ast.WhileStatement {
+	stmt := &ast.WhileStatement{Token: p.currentToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.NextToken()
+	stmt.Condition = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseLoopBody()
+
+	return stmt
+}
+
+func (p *Parser) parseForStatement() *ast.ForStatement {
+	stmt := &ast.ForStatement{Token: p.currentToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stmt.Variable = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	p.NextToken()
+	stmt.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseLoopBody()
+
+	return stmt
+}
+
+func (p *Parser) parseLoopBody() *ast.BlockStatement {
+	p.loopDepth++
+	defer func() { p.loopDepth-- }()
+
+	return p.parseBlockStatement()
+}
+
+func (p *Parser) parseBreakStatement() *ast.BreakStatement {
+	stmt := &ast.BreakStatement{Token: p.currentToken}
+
+	if p.loopDepth == 0 {
+		p.errorf(p.currentToken.Pos, "break outside loop")
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
+	stmt := &ast.ContinueStatement{Token: p.currentToken}
+
+	if p.loopDepth == 0 {
+		p.errorf(p.currentToken.Pos, "continue outside loop")
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.NextToken()
+	}
+
+	return stmt
+}
+
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{Token: p.currentToken}
 	stmt.Expression = p.parseExpression(LOWEST)
@@ -174,8 +380,7 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 func (p *Parser) parseExpression(precedence int) ast.Expression {
 	prefix := p.prefixParseFns[p.currentToken.Type]
 	if prefix == nil {
-		msg := fmt.Sprintf("no prefix parse function for %s found", p.currentToken.Type)
-		p.errors = append(p.errors, msg)
+		p.errorf(p.currentToken.Pos, "no prefix parse function for %s found", p.currentToken.Type)
 		return nil
 	}
 
@@ -196,6 +401,10 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 }
 
 func (p *Parser) parseIdentifier() ast.Expression {
+	if p.peekTokenIs(token.ARROW) && !p.guard {
+		return p.parseArrowFunction()
+	}
+
 	return &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
 }
 
@@ -207,9 +416,33 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	literal := &ast.IntegerLiteral{Token: p.currentToken}
 
 	value, err := strconv.ParseInt(p.currentToken.Literal, 0, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		// literals too large for an int64 are held as big integers
+		if value, ok := new(big.Int).SetString(p.currentToken.Literal, 0); ok {
+			literal.Big = value
+			return literal
+		}
+	}
+
 	if err != nil {
-		msg := fmt.Sprintf("could not parse %s as integer", p.currentToken.Literal)
-		p.errors = append(p.errors, msg)
+		p.errorf(p.currentToken.Pos, "invalid integer literal %s", p.currentToken.Literal)
+		return nil
+	}
+
+	literal.Value = value
+
+	return literal
+}
+
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	literal := &ast.FloatLiteral{Token: p.currentToken}
+
+	value, err := strconv.ParseFloat(p.currentToken.Literal, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		p.errorf(p.currentToken.Pos, "float literal %s out of range", p.currentToken.Literal)
+		return nil
+	} else if err != nil {
+		p.errorf(p.currentToken.Pos, "invalid float literal %s", p.currentToken.Literal)
 		return nil
 	}
 
@@ -226,6 +459,7 @@ func (p *Parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{Token: p.currentToken}
 
 	array.Elements = p.parseExpressionList(token.RBRACKET)
+	array.Rbracket = p.currentToken
 
 	return array
 }
@@ -258,6 +492,8 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 		return nil
 	}
 
+	hash.Rbrace = p.currentToken
+
 	return hash
 }
 
@@ -274,37 +510,368 @@ func (p *Parser) parseFunctionLiteral() ast.Expression {
 		return nil
 	}
 
+	// loops outside the function cannot be broken out of from within it
+	loopDepth, guard := p.loopDepth, p.guard
+	p.loopDepth, p.guard = 0, false
 	literal.Body = p.parseBlockStatement()
+	p.loopDepth, p.guard = loopDepth, guard
+
+	return literal
+}
+
+// parseArrowFunction parses the shorthand x => x * 2 or (a, b) => { ... },
+// starting from the parameter or the ( at the current token.
+func (p *Parser) parseArrowFunction() ast.Expression {
+	literal := &ast.FunctionLiteral{Token: p.currentToken}
+
+	if p.currentTokenIs(token.IDENT) {
+		literal.Parameters = []*ast.Parameter{{
+			Token:  p.currentToken,
+			Target: &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal},
+		}}
+	} else {
+		literal.Parameters = p.parseFunctionParameters()
+		if literal.Parameters == nil {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.ARROW) {
+		return nil
+	}
+
+	p.NextToken()
+
+	loopDepth, guard := p.loopDepth, p.guard
+	p.loopDepth, p.guard = 0, false
+	defer func() { p.loopDepth, p.guard = loopDepth, guard }()
+
+	if p.currentTokenIs(token.LBRACE) {
+		literal.Body = p.parseBlockStatement()
+		return literal
+	}
+
+	body := &ast.ExpressionStatement{Token: p.currentToken}
+
+	body.Expression = p.parseExpression(LOWEST)
+	if body.Expression == nil {
+		return nil
+	}
+
+	literal.Body = &ast.BlockStatement{Token: body.Token, Statements: []ast.Statement{body}}
 
 	return literal
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-	identifiers := []*ast.Identifier{}
+func (p *Parser) parseFunctionParameters() []*ast.Parameter {
+	parameters := []*ast.Parameter{}
 
 	if p.peekTokenIs(token.RPAREN) {
 		p.NextToken()
-		return identifiers
+		return parameters
 	}
 
-	p.NextToken()
+	for {
+		p.NextToken()
 
-	identifier := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
-	identifiers = append(identifiers, identifier)
+		parameter := p.parseParameter()
+		if parameter == nil {
+			return nil
+		}
 
-	for p.peekTokenIs(token.COMMA) {
+		if len(parameters) > 0 {
+			previous := parameters[len(parameters)-1]
+
+			if previous.Rest {
+				p.errorf(parameter.Pos(), "rest parameter must be last")
+				return nil
+			}
+
+			if previous.Default != nil && parameter.Default == nil && !parameter.Rest {
+				p.errorf(parameter.Pos(), "parameter %s without a default follows a parameter with a default", parameter.String())
+				return nil
+			}
+		}
+
+		parameters = append(parameters, parameter)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+
+		p.NextToken()
+	}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	return parameters
+}
+
+func (p *Parser) parseParameter() *ast.Parameter {
+	parameter := &ast.Parameter{Token: p.currentToken}
+
+	if p.currentTokenIs(token.ELLIPSIS) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		parameter.Target = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+		parameter.Rest = true
+
+		return parameter
+	}
+
+	parameter.Target = p.parsePattern()
+	if parameter.Target == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.ASSIGN) {
 		p.NextToken()
 		p.NextToken()
 
-		identifier := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
-		identifiers = append(identifiers, identifier)
+		parameter.Default = p.parseExpression(LOWEST)
+	}
+
+	return parameter
+}
+
+// parsePattern parses the target of a binding: an identifier, or an array or
+// hash pattern that destructures the bound value.
+func (p *Parser) parsePattern() ast.Expression {
+	switch p.currentToken.Type {
+	case token.IDENT:
+		return &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	case token.LBRACKET:
+		return p.parseArrayPattern()
+	case token.LBRACE:
+		return p.parseHashPattern()
+	case token.INT, token.FLOAT, token.STRING, token.TRUE, token.FALSE:
+		if p.refutable {
+			return p.prefixParseFns[p.currentToken.Type]()
+		}
+	case token.MINUS:
+		if p.refutable && (p.peekTokenIs(token.INT) || p.peekTokenIs(token.FLOAT)) {
+			return p.parsePrefixExpression()
+		}
+	}
+
+	p.errorf(p.currentToken.Pos, "expected identifier or pattern, got %s", p.currentToken.Type)
+	return nil
+}
+
+func (p *Parser) parseMatchExpression() ast.Expression {
+	expression := &ast.MatchExpression{Token: p.currentToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
 	}
 
+	p.NextToken()
+	expression.Value = p.parseExpression(LOWEST)
+
 	if !p.expectPeek(token.RPAREN) {
 		return nil
 	}
 
-	return identifiers
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.NextToken()
+
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+
+		expression.Arms = append(expression.Arms, arm)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	expression.Rbrace = p.currentToken
+
+	return expression
+}
+
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	arm := &ast.MatchArm{}
+
+	refutable := p.refutable
+	p.refutable = true
+	arm.Pattern = p.parsePattern()
+	p.refutable = refutable
+
+	if arm.Pattern == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.IF) {
+		p.NextToken()
+		p.NextToken()
+
+		guard := p.guard
+		p.guard = true
+		arm.Guard = p.parseExpression(LOWEST)
+		p.guard = guard
+	}
+
+	if !p.expectPeek(token.ARROW) {
+		return nil
+	}
+
+	p.NextToken()
+	arm.Body = p.parseExpression(LOWEST)
+
+	return arm
+}
+
+func (p *Parser) parseTryExpression() ast.Expression {
+	expression := &ast.TryExpression{Token: p.currentToken}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	expression.Block = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.CATCH) {
+		p.NextToken()
+
+		if !p.expectPeek(token.LPAREN) {
+			return nil
+		}
+
+		p.NextToken()
+
+		expression.Parameter = p.parsePattern()
+		if expression.Parameter == nil {
+			return nil
+		}
+
+		if !p.expectPeek(token.RPAREN) {
+			return nil
+		}
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		expression.Catch = p.parseBlockStatement()
+	}
+
+	if p.peekTokenIs(token.FINALLY) {
+		p.NextToken()
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		expression.Finally = p.parseBlockStatement()
+	}
+
+	if expression.Catch == nil && expression.Finally == nil {
+		p.errorf(p.peekToken.Pos, "expected catch or finally after try block, got %s", p.peekToken.Type)
+		return nil
+	}
+
+	return expression
+}
+
+func (p *Parser) parseArrayPattern() ast.Expression {
+	pattern := &ast.ArrayPattern{Token: p.currentToken}
+
+	for !p.peekTokenIs(token.RBRACKET) {
+		p.NextToken()
+
+		if p.currentTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+
+			pattern.Rest = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+			if !p.peekTokenIs(token.RBRACKET) {
+				p.errorf(p.peekToken.Pos, "rest element must be last in array pattern")
+				return nil
+			}
+
+			break
+		}
+
+		element := p.parsePattern()
+		if element == nil {
+			return nil
+		}
+
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !p.peekTokenIs(token.RBRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	pattern.Rbracket = p.currentToken
+
+	return pattern
+}
+
+func (p *Parser) parseHashPattern() ast.Expression {
+	pattern := &ast.HashPattern{Token: p.currentToken}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.NextToken()
+
+		if !p.currentTokenIs(token.IDENT) && !p.currentTokenIs(token.STRING) {
+			p.errorf(p.currentToken.Pos, "expected key in hash pattern, got %s", p.currentToken.Type)
+			return nil
+		}
+
+		pair := &ast.HashPatternPair{Key: p.currentToken.Literal}
+
+		if p.peekTokenIs(token.COLON) {
+			p.NextToken()
+			p.NextToken()
+
+			pair.Value = p.parsePattern()
+			if pair.Value == nil {
+				return nil
+			}
+		} else if p.currentTokenIs(token.IDENT) {
+			pair.Value = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+		} else {
+			p.expectPeek(token.COLON)
+			return nil
+		}
+
+		pattern.Pairs = append(pattern.Pairs, pair)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	pattern.Rbrace = p.currentToken
+
+	return pattern
 }
 
 func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
@@ -316,13 +883,13 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 	}
 
 	p.NextToken()
-	list = append(list, p.parseExpression(LOWEST))
+	list = append(list, p.parseListElement())
 
 	for p.peekTokenIs(token.COMMA) {
 		p.NextToken()
 		p.NextToken()
 
-		list = append(list, p.parseExpression(LOWEST))
+		list = append(list, p.parseListElement())
 	}
 
 	if !p.expectPeek(end) {
@@ -332,6 +899,21 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 	return list
 }
 
+// parseListElement parses an element of an array literal or argument list,
+// either an expression or an array spread into the list.
+func (p *Parser) parseListElement() ast.Expression {
+	if !p.currentTokenIs(token.ELLIPSIS) {
+		return p.parseExpression(LOWEST)
+	}
+
+	spread := &ast.SpreadElement{Token: p.currentToken}
+
+	p.NextToken()
+	spread.Value = p.parseExpression(LOWEST)
+
+	return spread
+}
+
 func (p *Parser) parsePrefixExpression() ast.Expression {
 	expression := &ast.PrefixExpression{
 		Token:    p.currentToken,
@@ -353,13 +935,152 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	}
 
 	precedence := p.currentPrecedence()
+	if p.currentTokenIs(token.POWER) {
+		// right associative: a ** b ** c is a ** (b ** c)
+		precedence--
+	}
+
 	p.NextToken()
 	expression.Right = p.parseExpression(precedence)
 
 	return expression
 }
 
+func (p *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	// an invalid target may be missing operands after an earlier error, so
+	// it is reported at the operator rather than by its own position or text
+	valid := false
+	switch target := target.(type) {
+	case *ast.Identifier:
+		valid = true
+	case *ast.IndexExpressopn:
+		valid = !target.Optional
+	case *ast.MemberExpression:
+		valid = !target.Optional
+	case nil:
+		return nil
+	}
+
+	if !valid {
+		p.errorf(p.currentToken.Pos, "invalid assignment target for %s", p.currentToken.Literal)
+		return nil
+	}
+
+	expression := &ast.AssignExpression{
+		Token:    p.currentToken,
+		Target:   target,
+		Operator: p.currentToken.Literal,
+	}
+
+	// right associative: a = b = c is a = (b = c)
+	precedence := p.currentPrecedence() - 1
+	p.NextToken()
+	expression.Value = p.parseExpression(precedence)
+
+	return expression
+}
+
+func (p *Parser) parseLogicalExpression(left ast.Expression) ast.Expression {
+	expression := &ast.LogicalExpression{
+		Token:    p.currentToken,
+		Left:     left,
+		Operator: p.currentToken.Literal,
+	}
+
+	precedence := p.currentPrecedence()
+	p.NextToken()
+	expression.Right = p.parseExpression(precedence)
+
+	return expression
+}
+
+func (p *Parser) parsePipeExpression(left ast.Expression) ast.Expression {
+	expression := &ast.PipeExpression{Token: p.currentToken, Left: left}
+
+	precedence := p.currentPrecedence()
+	p.NextToken()
+	expression.Right = p.parseExpression(precedence)
+
+	return expression
+}
+
+func (p *Parser) parseConditionalExpression(condition ast.Expression) ast.Expression {
+	expression := &ast.ConditionalExpression{Token: p.currentToken, Condition: condition}
+
+	p.NextToken()
+	expression.Consequence = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.COLON) {
+		return nil
+	}
+
+	// right associative: a ? b : c ? d : e is a ? b : (c ? d : e)
+	p.NextToken()
+	expression.Alternative = p.parseExpression(TERNARY - 1)
+
+	return expression
+}
+
+// parseOptionalChain parses a?.name, a?.[index] and f?.(args), which yield
+// null rather than failing when the left side is null.
+func (p *Parser) parseOptionalChain(left ast.Expression) ast.Expression {
+	switch {
+	case p.peekTokenIs(token.IDENT):
+		expression, ok := p.parseMemberExpression(left).(*ast.MemberExpression)
+		if !ok {
+			return nil
+		}
+
+		expression.Optional = true
+
+		return expression
+	case p.peekTokenIs(token.LBRACKET):
+		p.NextToken()
+
+		switch expression := p.parseIndexExpression(left).(type) {
+		case *ast.IndexExpressopn:
+			expression.Optional = true
+			return expression
+		case *ast.SliceExpression:
+			expression.Optional = true
+			return expression
+		default:
+			return nil
+		}
+	case p.peekTokenIs(token.LPAREN):
+		p.NextToken()
+
+		expression, ok := p.parseCallExpression(left).(*ast.CallExpression)
+		if !ok {
+			return nil
+		}
+
+		expression.Optional = true
+
+		return expression
+	default:
+		p.errorf(p.peekToken.Pos, "expected identifier, [ or ( after ?., got %s", p.peekToken.Type)
+		return nil
+	}
+}
+
+func (p *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	expression := &ast.MemberExpression{Token: p.currentToken, Object: object}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	expression.Property = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	return expression
+}
+
 func (p *Parser) parseGroupedExpression() ast.Expression {
+	if p.isArrowParameters() {
+		return p.parseArrowFunction()
+	}
+
 	p.NextToken()
 
 	expression := p.parseExpression(LOWEST)
@@ -372,6 +1093,34 @@ func (p *Parser) parseGroupedExpression() ast.Expression {
 
 }
 
+// isArrowParameters reports whether the ( at the current token opens the
+// parameter list of an arrow function. Only the => after the matching ) tells
+// it apart from a grouped expression, so the tokens up to it are looked ahead
+// at without being consumed.
+func (p *Parser) isArrowParameters() bool {
+	if p.guard {
+		return false
+	}
+
+	lexer := p.lexer.Clone()
+	depth := 1
+
+	for tok := p.peekToken; tok.Type != token.EOF; tok = lexer.NextToken() {
+		switch tok.Type {
+		case token.LPAREN:
+			depth++
+		case token.RPAREN:
+			depth--
+
+			if depth == 0 {
+				return lexer.NextToken().Type == token.ARROW
+			}
+		}
+	}
+
+	return false
+}
+
 func (p *Parser) parseIfExpression() ast.Expression {
 	expression := ast.IfExpression{Token: p.currentToken}
 
@@ -407,22 +1156,116 @@ func (p *Parser) parseIfExpression() ast.Expression {
 
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
 	expression := &ast.CallExpression{Token: p.currentToken, Function: function}
-	expression.Arguments = p.parseExpressionList(token.RPAREN)
+
+	if !p.parseCallArguments(expression) {
+		return nil
+	}
+
+	expression.Rparen = p.currentToken
 
 	return expression
 }
 
+// parseCallArguments parses the positional arguments of a call followed by
+// its keyword arguments, as in f(x, ...rest, verbose: true).
+func (p *Parser) parseCallArguments(call *ast.CallExpression) bool {
+	call.Arguments = []ast.Expression{}
+
+	// arguments are delimited, so => inside them cannot end a match guard
+	guard := p.guard
+	p.guard = false
+	defer func() { p.guard = guard }()
+
+	if p.peekTokenIs(token.RPAREN) {
+		p.NextToken()
+		return true
+	}
+
+	for {
+		p.NextToken()
+
+		if p.currentTokenIs(token.IDENT) && p.peekTokenIs(token.COLON) {
+			keyword := &ast.KeywordArgument{
+				Token: p.currentToken,
+				Name:  &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal},
+			}
+
+			p.NextToken()
+			p.NextToken()
+
+			keyword.Value = p.parseExpression(LOWEST)
+
+			// a duplicate is still parsed so that parsing resumes after it
+			duplicate := false
+			for _, other := range call.Keywords {
+				if other.Name.Value == keyword.Name.Value {
+					duplicate = true
+				}
+			}
+
+			if duplicate {
+				p.errorf(keyword.Pos(), "duplicate keyword argument %s", keyword.Name.Value)
+			} else {
+				call.Keywords = append(call.Keywords, keyword)
+			}
+		} else if len(call.Keywords) > 0 {
+			p.errorf(p.currentToken.Pos, "positional argument follows keyword argument")
+			p.parseListElement()
+		} else {
+			call.Arguments = append(call.Arguments, p.parseListElement())
+		}
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+
+		p.NextToken()
+	}
+
+	return p.expectPeek(token.RPAREN)
+}
+
+// parseIndexExpression parses an index a[i] or a slice a[i:j], where either
+// bound of the slice may be omitted.
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	expression := &ast.IndexExpressopn{Token: p.currentToken, Left: left}
+	lbracket := p.currentToken
 
-	p.NextToken()
+	var index ast.Expression
+	if !p.peekTokenIs(token.COLON) {
+		p.NextToken()
+		index = p.parseExpression(LOWEST)
+	}
+
+	if p.peekTokenIs(token.COLON) {
+		p.NextToken()
+		return p.parseSliceExpression(lbracket, left, index)
+	}
 
-	expression.Index = p.parseExpression(LOWEST)
+	expression := &ast.IndexExpressopn{Token: lbracket, Left: left, Index: index}
 
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
 	}
 
+	expression.Rbracket = p.currentToken
+
+	return expression
+}
+
+func (p *Parser) parseSliceExpression(lbracket token.Token, left, low ast.Expression) ast.Expression {
+	expression := &ast.SliceExpression{Token: lbracket, Left: left, Low: low}
+
+	if !p.peekTokenIs(token.RBRACKET) {
+		p.NextToken()
+		expression.High = p.parseExpression(LOWEST)
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	expression.Rbracket = p.currentToken
+
 	return expression
 }
 
@@ -440,6 +1283,8 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 		p.NextToken()
 	}
 
+	statement.Rbrace = p.currentToken
+
 	return statement
 }
 
@@ -463,9 +1308,14 @@ func (p *Parser) expectPeek(t token.TokenType) bool {
 }
 
 func (p *Parser) peekError(t token.TokenType) {
-	msg := fmt.Sprintf("expected next token to be %s, got %s instead", t, p.peekToken.Type)
+	p.errorf(p.peekToken.Pos, "expected next token to be %s, got %s instead", t, p.peekToken.Type)
+}
+
+// errorf records a parse error prefixed with the position it occurred at.
+func (p *Parser) errorf(pos token.Position, format string, a ...interface{}) {
+	msg := fmt.Sprintf(format, a...)
 
-	p.errors = append(p.errors, msg)
+	p.errors = append(p.errors, fmt.Sprintf("%s: %s", pos, msg))
 }
 
 func (p *Parser) registerPrefix(tokenType token.TokenType, fn prefixParseFn) {
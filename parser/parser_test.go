@@ -105,6 +105,119 @@ func Test_ParsingLetStatements(t *testing.T) {
 	}
 }
 
+func Test_ParsingConstStatement(t *testing.T) {
+	input := "const limit = 10;"
+
+	l := lexer.NewLexer(input)
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+	assert.Len(t, program.Statements, 1)
+
+	statement, ok := program.Statements[0].(*ast.LetStatement)
+	assert.True(t, ok)
+
+	assert.Equal(t, "const", statement.TokenLiteral())
+	testIdentifier(t, statement.Name, "limit")
+	testIntegerLiteral(t, statement.Value, 10)
+	assert.Equal(t, "const limit = 10;", statement.String())
+}
+
+func Test_ParsingDestructuringLetStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b] = arr;", "let [a, b] = arr;"},
+		{"let [first, ...rest] = arr;", "let [first, ...rest] = arr;"},
+		{"let [] = arr;", "let [] = arr;"},
+		{"let [a, [b, c]] = arr;", "let [a, [b, c]] = arr;"},
+		{"let {name, age: years} = h;", "let {name, age: years} = h;"},
+		{`let {"first name": first} = h;`, "let {first name: first} = h;"},
+		{"const {point: [x, y]} = h;", "const {point: [x, y]} = h;"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		program := p.Parse()
+
+		assert.Len(t, p.errors, 0)
+		assert.Len(t, program.Statements, 1)
+
+		statement, ok := program.Statements[0].(*ast.LetStatement)
+		assert.True(t, ok)
+
+		assert.Nil(t, statement.Name)
+		assert.NotNil(t, statement.Pattern)
+		assert.Equal(t, test.expected, statement.String())
+	}
+}
+
+func Test_ParsingArrayPattern(t *testing.T) {
+	input := "let [a, ...rest] = arr;"
+
+	l := lexer.NewLexer(input)
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+
+	pattern, ok := program.Statements[0].(*ast.LetStatement).Pattern.(*ast.ArrayPattern)
+	assert.True(t, ok)
+
+	assert.Len(t, pattern.Elements, 1)
+	testIdentifier(t, pattern.Elements[0], "a")
+	testIdentifier(t, pattern.Rest, "rest")
+}
+
+func Test_ParsingHashPattern(t *testing.T) {
+	input := "let {name, age: years} = h;"
+
+	l := lexer.NewLexer(input)
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+
+	pattern, ok := program.Statements[0].(*ast.LetStatement).Pattern.(*ast.HashPattern)
+	assert.True(t, ok)
+
+	assert.Len(t, pattern.Pairs, 2)
+	assert.Equal(t, "name", pattern.Pairs[0].Key)
+	testIdentifier(t, pattern.Pairs[0].Value, "name")
+	assert.Equal(t, "age", pattern.Pairs[1].Key)
+	testIdentifier(t, pattern.Pairs[1].Value, "years")
+}
+
+func Test_ParsingPatternErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, ...rest, b] = arr;", "1:16: rest element must be last in array pattern"},
+		{"let [a, 1] = arr;", "1:9: expected identifier or pattern, got INT"},
+		{"let {1: a} = h;", "1:6: expected key in hash pattern, got INT"},
+		{`let {"a"} = h;`, "1:9: expected next token to be :, got } instead"},
+		{"fn(a, [b, c) {}", "1:12: expected next token to be ,, got ) instead"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		p.Parse()
+
+		assert.NotEmpty(t, p.Errors())
+		assert.Equal(t, test.expected, p.Errors()[0])
+	}
+}
+
 func Test_ParsingReturnStatements(t *testing.T) {
 	tests := []struct {
 		input         string
@@ -187,6 +300,114 @@ func Test_IntegerLiteralExpression(t *testing.T) {
 	testIntegerLiteral(t, stmt.Expression, int64(5))
 }
 
+func Test_IntegerLiteralBases(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"0xFF;", 255},
+		{"0o755;", 493},
+		{"0b1010;", 10},
+		{"1_000_000;", 1000000},
+		{"0x_7fff_ffff_ffff_ffff;", 9223372036854775807},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		program := p.Parse()
+
+		assert.Len(t, p.errors, 0)
+		assert.Len(t, program.Statements, 1)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		assert.True(t, ok)
+
+		literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, literal.Value)
+	}
+}
+
+func Test_BigIntegerLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"9223372036854775808;", "9223372036854775808"},
+		{"0x1_0000_0000_0000_0000;", "18446744073709551616"},
+		{"0b1_0000000000000000000000000000000000000000000000000000000000000000;", "18446744073709551616"},
+		{"100000000000000000000;", "100000000000000000000"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		program := p.Parse()
+
+		assert.Len(t, p.errors, 0)
+		assert.Len(t, program.Statements, 1)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		assert.True(t, ok)
+
+		literal, ok := stmt.Expression.(*ast.IntegerLiteral)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, literal.Big.String())
+	}
+}
+
+func Test_NumberLiteralErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 + 0b102", "1:5: invalid integer literal 0b102"},
+		{"1__000", "1:1: invalid integer literal 1__000"},
+		{"1e400", "1:1: float literal 1e400 out of range"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		p.Parse()
+
+		assert.NotEmpty(t, p.Errors())
+		assert.Equal(t, test.expected, p.Errors()[0])
+	}
+}
+
+func Test_FloatLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{"3.14;", 3.14},
+		{"1e-9;", 1e-9},
+		{"2.5E3;", 2500},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		program := p.Parse()
+
+		assert.Len(t, p.errors, 0)
+		assert.Len(t, program.Statements, 1)
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		assert.True(t, ok)
+
+		literal, ok := stmt.Expression.(*ast.FloatLiteral)
+		assert.True(t, ok)
+		assert.Equal(t, test.expected, literal.Value)
+	}
+}
+
 func Test_PrefixExpression(t *testing.T) {
 	tests := []struct {
 		input        string
@@ -232,6 +453,10 @@ func Test_InfixExpressions(t *testing.T) {
 		{"5 - 5;", 5, "-", 5},
 		{"5 * 5;", 5, "*", 5},
 		{"5 / 5;", 5, "/", 5},
+		{"5 % 5;", 5, "%", 5},
+		{"5 ** 5;", 5, "**", 5},
+		{"5 <= 5;", 5, "<=", 5},
+		{"5 >= 5;", 5, ">=", 5},
 		{"5 > 5;", 5, ">", 5},
 		{"5 < 5;", 5, "<", 5},
 		{"5 == 5;", 5, "==", 5},
@@ -291,6 +516,66 @@ func Test_OperatorPrecedenceParsing(t *testing.T) {
 			"a + b / c",
 			"(a + (b / c))",
 		},
+		{
+			"a + b % c * d",
+			"(a + ((b % c) * d))",
+		},
+		{
+			"a * b ** c",
+			"(a * (b ** c))",
+		},
+		{
+			"a ** b ** c",
+			"(a ** (b ** c))",
+		},
+		{
+			"-a ** b",
+			"(-(a ** b))",
+		},
+		{
+			"a ** -b",
+			"(a ** (-b))",
+		},
+		{
+			"a + b <= c == d >= e",
+			"(((a + b) <= c) == (d >= e))",
+		},
+		{
+			"a || b && c",
+			"(a || (b && c))",
+		},
+		{
+			"a && b || c && d",
+			"((a && b) || (c && d))",
+		},
+		{
+			"a == b && c < d || !e",
+			"(((a == b) && (c < d)) || (!e))",
+		},
+		{
+			"a | b ^ c & d",
+			"(a | (b ^ (c & d)))",
+		},
+		{
+			"a & mask == 0",
+			"((a & mask) == 0)",
+		},
+		{
+			"1 << n + 1",
+			"(1 << (n + 1))",
+		},
+		{
+			"a >> 2 & 0xFF",
+			"((a >> 2) & 0xFF)",
+		},
+		{
+			"~a & b",
+			"((~a) & b)",
+		},
+		{
+			"a | b && c | d",
+			"((a | b) && (c | d))",
+		},
 		{
 			"a + b * c + d / e - f",
 			"(((a + (b * c)) + (d / e)) - f)",
@@ -371,18 +656,437 @@ func Test_OperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
 		},
+		{
+			"a = b = c + 1",
+			"(a = (b = (c + 1)))",
+		},
+		{
+			"a[i + 1] += b || c",
+			"((a[(i + 1)]) += (b || c))",
+		},
+		{
+			"a ? b : c ? d : e",
+			"(a ? b : (c ? d : e))",
+		},
+		{
+			"x = a || b ? c + 1 : d",
+			"(x = ((a || b) ? (c + 1) : d))",
+		},
+		{
+			"a ? b ? c : d : e",
+			"(a ? (b ? c : d) : e)",
+		},
+		{
+			"a ?? b || c",
+			"(a ?? (b || c))",
+		},
+		{
+			"a ?? b ?? c",
+			"((a ?? b) ?? c)",
+		},
+		{
+			"a ?? b ? c : d",
+			"((a ?? b) ? c : d)",
+		},
+		{
+			"a?.[0]?.[1] + f?.(x, y)",
+			"(((a?.[0])?.[1]) + f?.(x, y))",
+		},
+		{
+			"-a?.[0]",
+			"(-(a?.[0]))",
+		},
+		{
+			"a.b.c + d.e(1)[0]",
+			"(((a.b).c) + ((d.e)(1)[0]))",
+		},
+		{
+			"-h.x ** 2",
+			"(-((h.x) ** 2))",
+		},
+		{
+			"h?.user?.name ?? \"anon\"",
+			"(((h?.user)?.name) ?? anon)",
+		},
+		{
+			"h.count += 1",
+			"((h.count) += 1)",
+		},
+		{
+			"0..n + 1",
+			"(0 .. (n + 1))",
+		},
+		{
+			"a..=b == c",
+			"((a ..= b) == c)",
+		},
+		{
+			"a[1:len(a) - 1]",
+			"(a[1:(len(a) - 1)])",
+		},
+		{
+			"a[:-1] + s[2:] + b[:]",
+			"(((a[:(-1)]) + (s[2:])) + (b[:]))",
+		},
+		{
+			"a?.[1:]",
+			"(a?.[1:])",
+		},
+		{
+			"a |> f(b) |> g",
+			"((a |> f(b)) |> g)",
+		},
+		{
+			"x = a |> f",
+			"(x = (a |> f))",
+		},
+		{
+			"a + 1 |> f",
+			"((a + 1) |> f)",
+		},
+		{
+			"a ?? b |> f ? c : d",
+			"((a ?? b) |> (f ? c : d))",
+		},
+		{
+			"a || b |> h.f(1)",
+			"((a || b) |> (h.f)(1))",
+		},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		program := p.Parse()
+
+		assert.Len(t, p.errors, 0)
+		assert.NotNil(t, program)
+
+		assert.Equal(t, test.expected, program.String())
+	}
+}
+
+func Test_LogicalExpression(t *testing.T) {
+	input := "a && b;"
+
+	l := lexer.NewLexer(input)
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+	assert.Len(t, program.Statements, 1)
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	assert.True(t, ok)
+
+	expression, ok := stmt.Expression.(*ast.LogicalExpression)
+	assert.True(t, ok)
+
+	testIdentifier(t, expression.Left, "a")
+	assert.Equal(t, "&&", expression.Operator)
+	testIdentifier(t, expression.Right, "b")
+}
+
+func Test_WhileStatement(t *testing.T) {
+	input := "while (x < 10) { x; break; continue; }"
+
+	l := lexer.NewLexer(input)
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+	assert.Len(t, program.Statements, 1)
+
+	stmt, ok := program.Statements[0].(*ast.WhileStatement)
+	assert.True(t, ok)
+
+	testInfixExpression(t, stmt.Condition, "x", "<", 10)
+	assert.Len(t, stmt.Body.Statements, 3)
+
+	_, ok = stmt.Body.Statements[1].(*ast.BreakStatement)
+	assert.True(t, ok)
+
+	_, ok = stmt.Body.Statements[2].(*ast.ContinueStatement)
+	assert.True(t, ok)
+}
+
+func Test_ForStatement(t *testing.T) {
+	input := "for (item in items) { item }"
+
+	l := lexer.NewLexer(input)
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+	assert.Len(t, program.Statements, 1)
+
+	stmt, ok := program.Statements[0].(*ast.ForStatement)
+	assert.True(t, ok)
+
+	testIdentifier(t, stmt.Variable, "item")
+	testIdentifier(t, stmt.Iterable, "items")
+	assert.Len(t, stmt.Body.Statements, 1)
+	assert.Equal(t, "for (item in items) item", stmt.String())
+}
+
+func Test_BreakOutsideLoop(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"break;", "1:1: break outside loop"},
+		{"if (true) { continue; }", "1:13: continue outside loop"},
+		{"while (true) { let f = fn() { break; }; }", "1:31: break outside loop"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		p.Parse()
+
+		assert.Equal(t, []string{test.expected}, p.Errors())
+	}
+}
+
+func Test_InvalidAssignmentTarget(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1 = 2;", "1:3: invalid assignment target for ="},
+		{"a?.[0] = 2;", "1:8: invalid assignment target for ="},
+		{"a?.b = 2;", "1:6: invalid assignment target for ="},
+		{"f(x) += 1;", "1:6: invalid assignment target for +="},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		p.Parse()
+
+		assert.NotEmpty(t, p.Errors())
+		assert.Equal(t, test.expected, p.Errors()[0])
+	}
+}
+
+func Test_InvalidAssignmentTargetAfterError(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"0b2 + 1 = 3", []string{"1:1: invalid integer literal 0b2", "1:9: invalid assignment target for ="}},
+		{"-0b2 = 1", []string{"1:2: invalid integer literal 0b2", "1:6: invalid assignment target for ="}},
+		{"0b2?.[0] = 1", []string{"1:1: invalid integer literal 0b2", "1:10: invalid assignment target for ="}},
+		{"(0b2 + 1) += 1", []string{"1:2: invalid integer literal 0b2", "1:11: invalid assignment target for +="}},
+		{"!0b2 || x = 1", []string{"1:2: invalid integer literal 0b2", "1:11: invalid assignment target for ="}},
+		{"0b2 |> f = 1", []string{"1:1: invalid integer literal 0b2", "1:10: invalid assignment target for ="}},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		assert.NotPanics(t, func() { p.Parse() })
+		assert.Equal(t, test.expected, p.Errors())
+	}
+}
+
+func Test_ConditionalOperatorErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a ? b", "1:6: expected next token to be :, got EOF instead"},
+		{"a ? b c", "1:7: expected next token to be :, got IDENT instead"},
+		{"a?.1", "1:4: expected identifier, [ or ( after ?., got INT"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		p.Parse()
+
+		assert.NotEmpty(t, p.Errors())
+		assert.Equal(t, test.expected, p.Errors()[0])
+	}
+}
+
+func Test_TryExpression(t *testing.T) {
+	input := "try { f(); } catch (e) { e.message } finally { done(); }"
+
+	l := lexer.NewLexer(input)
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+	assert.Len(t, program.Statements, 1)
+
+	expression, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.TryExpression)
+	assert.True(t, ok)
+
+	assert.Len(t, expression.Block.Statements, 1)
+	testIdentifier(t, expression.Parameter, "e")
+	assert.Len(t, expression.Catch.Statements, 1)
+	assert.Len(t, expression.Finally.Statements, 1)
+	assert.Equal(t, len(input), expression.End().Offset)
+	assert.Equal(t, "try f() catch (e) (e.message) finally done()", expression.String())
+}
+
+func Test_TryAndThrowForms(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { a } catch (e) { b }", "try a catch (e) b"},
+		{"try { a } finally { b }", "try a finally b"},
+		{"try { a } catch ({message, kind}) { message }", "try a catch ({message, kind}) message"},
+		{"let x = try { f() } catch (e) { 0 };", "let x = try f() catch (e) 0;"},
+		{"throw \"bad\";", "throw bad;"},
+		{"throw {\"message\": m}", "throw {message:m};"},
+		{"fn() { throw e }", "fn()throw e;"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		program := p.Parse()
+
+		assert.Len(t, p.errors, 0, fmt.Sprintf("for input: %v", test.input))
+		assert.Equal(t, test.expected, program.String())
+	}
+}
+
+func Test_TryExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"try { a }", "1:10: expected catch or finally after try block, got EOF"},
+		{"try { a } b", "1:11: expected catch or finally after try block, got IDENT"},
+		{"try a", "1:5: expected next token to be {, got IDENT instead"},
+		{"try { a } catch { b }", "1:17: expected next token to be (, got { instead"},
+		{"try { a } catch () { b }", "1:18: expected identifier or pattern, got )"},
+		{"try { a } catch (1) { b }", "1:18: expected identifier or pattern, got INT"},
+		{"try { a } finally b", "1:19: expected next token to be {, got IDENT instead"},
+		{"throw;", "1:6: no prefix parse function for ; found"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		p.Parse()
+
+		assert.NotEmpty(t, p.Errors())
+		assert.Equal(t, test.expected, p.Errors()[0])
+	}
+}
+
+func Test_MemberExpression(t *testing.T) {
+	input := "user.name"
+
+	l := lexer.NewLexer(input)
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+
+	member, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.MemberExpression)
+	assert.True(t, ok)
+
+	testIdentifier(t, member.Object, "user")
+	testIdentifier(t, member.Property, "name")
+	assert.False(t, member.Optional)
+}
+
+func Test_MemberExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a.1", "1:3: expected next token to be IDENT, got INT instead"},
+		{"a.[0]", "1:3: expected next token to be IDENT, got [ instead"},
+		{"a.", "1:3: expected next token to be IDENT, got EOF instead"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		p.Parse()
+
+		assert.NotEmpty(t, p.Errors())
+		assert.Equal(t, test.expected, p.Errors()[0])
+	}
+}
+
+func Test_SliceExpression(t *testing.T) {
+	tests := []struct {
+		input string
+		low   interface{}
+		high  interface{}
+	}{
+		{"a[1:3]", 1, 3},
+		{"a[1:]", 1, nil},
+		{"a[:3]", nil, 3},
+		{"a[:]", nil, nil},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		program := p.Parse()
+
+		assert.Len(t, p.errors, 0)
+
+		slice, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.SliceExpression)
+		assert.True(t, ok)
+
+		testIdentifier(t, slice.Left, "a")
+
+		if test.low == nil {
+			assert.Nil(t, slice.Low)
+		} else {
+			testliteralExpression(t, slice.Low, test.low)
+		}
+
+		if test.high == nil {
+			assert.Nil(t, slice.High)
+		} else {
+			testliteralExpression(t, slice.High, test.high)
+		}
+	}
+}
+
+func Test_SliceExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a[1:2:3]", "1:6: expected next token to be ], got : instead"},
+		{"a[1:2", "1:6: expected next token to be ], got EOF instead"},
+		{"a[1:3] = 2", "1:8: invalid assignment target for ="},
 	}
 
 	for _, test := range tests {
 		l := lexer.NewLexer(test.input)
 		p := NewParser(l)
 
-		program := p.Parse()
-
-		assert.Len(t, p.errors, 0)
-		assert.NotNil(t, program)
+		p.Parse()
 
-		assert.Equal(t, test.expected, program.String())
+		assert.NotEmpty(t, p.Errors())
+		assert.Equal(t, test.expected, p.Errors()[0])
 	}
 }
 
@@ -465,8 +1169,8 @@ func Test_FunctionLiteral(t *testing.T) {
 	assert.True(t, ok)
 	assert.Len(t, expression.Parameters, 2)
 
-	testliteralExpression(t, expression.Parameters[0], "x")
-	testliteralExpression(t, expression.Parameters[1], "y")
+	testliteralExpression(t, expression.Parameters[0].Target, "x")
+	testliteralExpression(t, expression.Parameters[1].Target, "y")
 
 	assert.Len(t, expression.Body.Statements, 1)
 
@@ -475,6 +1179,25 @@ func Test_FunctionLiteral(t *testing.T) {
 	testInfixExpression(t, bodyStmt.Expression, "x", "+", "y")
 }
 
+func Test_FunctionLiteralWithName(t *testing.T) {
+	input := "let myFunction = fn() { };"
+
+	l := lexer.NewLexer(input)
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+	assert.Len(t, program.Statements, 1)
+
+	stmt, ok := program.Statements[0].(*ast.LetStatement)
+	assert.True(t, ok)
+
+	function, ok := stmt.Value.(*ast.FunctionLiteral)
+	assert.True(t, ok)
+	assert.Equal(t, "myFunction", function.Name)
+}
+
 func Test_FunctionParameter(t *testing.T) {
 	tests := []struct {
 		input          string
@@ -504,8 +1227,260 @@ func Test_FunctionParameter(t *testing.T) {
 		assert.Len(t, function.Parameters, len(test.expectedParams))
 
 		for i, identifier := range test.expectedParams {
-			testliteralExpression(t, function.Parameters[i], identifier)
+			testliteralExpression(t, function.Parameters[i].Target, identifier)
+		}
+	}
+}
+
+func Test_FunctionPatternParameters(t *testing.T) {
+	input := "fn([a, b], {name}, c) { a }"
+
+	l := lexer.NewLexer(input)
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+
+	function, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	assert.True(t, ok)
+
+	assert.Len(t, function.Parameters, 3)
+
+	_, ok = function.Parameters[0].Target.(*ast.ArrayPattern)
+	assert.True(t, ok)
+
+	_, ok = function.Parameters[1].Target.(*ast.HashPattern)
+	assert.True(t, ok)
+
+	testIdentifier(t, function.Parameters[2].Target, "c")
+	assert.Equal(t, "fn([a, b], {name}, c)a", function.String())
+}
+
+func Test_FunctionDefaultAndRestParameters(t *testing.T) {
+	input := "fn(a, b = 10, ...rest) { a }"
+
+	l := lexer.NewLexer(input)
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+
+	function, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+	assert.True(t, ok)
+
+	assert.Len(t, function.Parameters, 3)
+
+	testIdentifier(t, function.Parameters[0].Target, "a")
+	assert.Nil(t, function.Parameters[0].Default)
+	assert.False(t, function.Parameters[0].Rest)
+
+	testIdentifier(t, function.Parameters[1].Target, "b")
+	testIntegerLiteral(t, function.Parameters[1].Default, 10)
+
+	testIdentifier(t, function.Parameters[2].Target, "rest")
+	assert.True(t, function.Parameters[2].Rest)
+
+	assert.Equal(t, "fn(a, b = 10, ...rest)a", function.String())
+}
+
+func Test_ArrowFunctions(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedParams []string
+		expected       string
+	}{
+		{"x => x * 2", []string{"x"}, "fn(x)(x * 2)"},
+		{"() => 1", []string{}, "fn()1"},
+		{"(a, b) => { a + b }", []string{"a", "b"}, "fn(a, b)(a + b)"},
+		{"(a, b = 1, ...rest) => a", []string{"a", "b = 1", "...rest"}, "fn(a, b = 1, ...rest)a"},
+		{"([a, b], {c}) => a", []string{"[a, b]", "{c}"}, "fn([a, b], {c})a"},
+		{"x => y => x + y", []string{"x"}, "fn(x)fn(y)(x + y)"},
+		{"((x)) => x", []string{}, ""},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		program := p.Parse()
+
+		if test.expected == "" {
+			assert.NotEmpty(t, p.Errors(), fmt.Sprintf("for input: %v", test.input))
+			continue
+		}
+
+		assert.Len(t, p.errors, 0)
+		assert.Len(t, program.Statements, 1)
+
+		function, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.FunctionLiteral)
+		assert.True(t, ok)
+
+		assert.Len(t, function.Parameters, len(test.expectedParams))
+		for i, param := range test.expectedParams {
+			assert.Equal(t, param, function.Parameters[i].String())
 		}
+
+		assert.Equal(t, test.expected, function.String())
+		assert.Equal(t, len(test.input), function.End().Offset)
+	}
+}
+
+func Test_ArrowFunctionsAndGroupedExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(a + b) * c", "((a + b) * c)"},
+		{"(f(x)) + (y)", "(f(x) + y)"},
+		{"let double = x => x * 2;", "let double = fn(x)(x * 2);"},
+		{"map(xs, (x) => x + 1)", "map(xs, fn(x)(x + 1))"},
+		{"f(key: x => x)", "f(key: fn(x)x)"},
+		{"a |> (x => x + 1)", "(a |> fn(x)(x + 1))"},
+		{"match (x) { n if ok => n }", "match (x) { n if ok => n }"},
+		{"match (x) { n if (ok) => n }", "match (x) { n if ok => n }"},
+		{"match (x) { n if any(n, y => y > 0) => n }", "match (x) { n if any(n, fn(y)(y > 0)) => n }"},
+		{"match (x) { n => y => n + y }", "match (x) { n => fn(y)(n + y) }"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		program := p.Parse()
+
+		assert.Len(t, p.errors, 0, fmt.Sprintf("for input: %v", test.input))
+		assert.Equal(t, test.expected, program.String())
+	}
+}
+
+func Test_ArrowFunctionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"(1) => 1", "1:2: expected identifier or pattern, got INT"},
+		{"(a + b) => a", "1:4: expected next token to be ), got + instead"},
+		{"x =>", "1:5: no prefix parse function for EOF found"},
+		{"while (true) { let f = () => { break; }; }", "1:32: break outside loop"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		p.Parse()
+
+		assert.NotEmpty(t, p.Errors())
+		assert.Equal(t, test.expected, p.Errors()[0])
+	}
+}
+
+func Test_FunctionParameterErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fn(...rest, a) {}", "1:13: rest parameter must be last"},
+		{"fn(a = 1, b) {}", "1:11: parameter b without a default follows a parameter with a default"},
+		{"fn(...[a]) {}", "1:7: expected next token to be IDENT, got [ instead"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		p.Parse()
+
+		assert.NotEmpty(t, p.Errors())
+		assert.Equal(t, test.expected, p.Errors()[0])
+	}
+}
+
+func Test_SpreadElements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[...a, ...b]", "[...a, ...b]"},
+		{"[0, ...a + b]", "[0, ...(a + b)]"},
+		{"f(x, ...args)", "f(x, ...args)"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		program := p.Parse()
+
+		assert.Len(t, p.errors, 0)
+		assert.Equal(t, test.expected, program.String())
+	}
+}
+
+func Test_MatchExpression(t *testing.T) {
+	input := `match (shape) {
+		0 => "zero",
+		[x, -1.5, ...rest] if x > 0 => x,
+		{kind: "circle", radius} => radius,
+		_ => false,
+	}`
+
+	l := lexer.NewLexer(input)
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+	assert.Len(t, program.Statements, 1)
+
+	match, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.MatchExpression)
+	assert.True(t, ok)
+
+	testIdentifier(t, match.Value, "shape")
+	assert.Len(t, match.Arms, 4)
+
+	testIntegerLiteral(t, match.Arms[0].Pattern, 0)
+	assert.Nil(t, match.Arms[0].Guard)
+	testStringLiteral(t, match.Arms[0].Body, "zero")
+
+	_, ok = match.Arms[1].Pattern.(*ast.ArrayPattern)
+	assert.True(t, ok)
+	testInfixExpression(t, match.Arms[1].Guard, "x", ">", 0)
+
+	_, ok = match.Arms[2].Pattern.(*ast.HashPattern)
+	assert.True(t, ok)
+
+	testIdentifier(t, match.Arms[3].Pattern, "_")
+	testBooleanLiteral(t, match.Arms[3].Body, false)
+
+	assert.Equal(t,
+		"match (shape) { 0 => zero, [x, (-1.5), ...rest] if (x > 0) => x, {kind: circle, radius} => radius, _ => false }",
+		match.String())
+}
+
+func Test_MatchExpressionErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (x) { 1 2 }", "1:15: expected next token to be =>, got INT instead"},
+		{"match (x) { 1 => a b => c }", "1:20: expected next token to be ,, got IDENT instead"},
+		{"match (x) { f(1) => a }", "1:14: expected next token to be =>, got ( instead"},
+		{"match (x) { -a => a }", "1:13: expected identifier or pattern, got -"},
+		{"match x { _ => 1 }", "1:7: expected next token to be (, got IDENT instead"},
+		{"match (x) { [a] => fn(1) { a } }", "1:23: expected identifier or pattern, got INT"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		p.Parse()
+
+		assert.NotEmpty(t, p.Errors())
+		assert.Equal(t, test.expected, p.Errors()[0])
 	}
 }
 
@@ -568,6 +1543,72 @@ func Test_CallExpressionParameter(t *testing.T) {
 	}
 }
 
+func Test_CallExpressionKeywordArguments(t *testing.T) {
+	input := "save(path, ...rest, verbose: true, mode: 1 + 2)"
+
+	l := lexer.NewLexer(input)
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+
+	call, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+	assert.True(t, ok)
+
+	assert.Len(t, call.Arguments, 2)
+	testIdentifier(t, call.Arguments[0], "path")
+
+	assert.Len(t, call.Keywords, 2)
+	testIdentifier(t, call.Keywords[0].Name, "verbose")
+	testBooleanLiteral(t, call.Keywords[0].Value, true)
+	testIdentifier(t, call.Keywords[1].Name, "mode")
+	testInfixExpression(t, call.Keywords[1].Value, 1, "+", 2)
+
+	assert.Equal(t, "save(path, ...rest, verbose: true, mode: (1 + 2))", call.String())
+}
+
+func Test_CallExpressionKeywordArgumentErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"f(a: 1, 2)", "1:9: positional argument follows keyword argument"},
+		{"f(a: 1, a: 2)", "1:9: duplicate keyword argument a"},
+		{"f(1: 2)", "1:4: expected next token to be ), got : instead"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		p.Parse()
+
+		assert.NotEmpty(t, p.Errors())
+		assert.Equal(t, test.expected, p.Errors()[0])
+	}
+}
+
+func Test_CallExpressionKeywordArgumentRecovery(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"f(a: 1, a: 2)", "1:9: duplicate keyword argument a"},
+		{"f(a: 1, a: 2, b: 3); g(x)", "1:9: duplicate keyword argument a"},
+		{"f(a: 1, 2, b: 3); g(x)", "1:9: positional argument follows keyword argument"},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input)
+		p := NewParser(l)
+
+		p.Parse()
+
+		assert.Equal(t, []string{test.expected}, p.Errors(), fmt.Sprintf("for input: %v", test.input))
+	}
+}
+
 func Test_StringLiteralExpression(t *testing.T) {
 	input := `"hello world";`
 
@@ -791,3 +1832,70 @@ func Test_ParsingHashLiteralWithExpressions(t *testing.T) {
 		expectedFunc(value)
 	}
 }
+
+func Test_NodePositions(t *testing.T) {
+	input := `let add = fn(x, y) {
+	x + y;
+};
+add(1, [2, 3][0]);`
+
+	l := lexer.NewLexer(input, lexer.WithFilename("main.mk"))
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+	assert.Len(t, program.Statements, 2)
+
+	assert.Equal(t, "main.mk:1:1", program.Pos().String())
+	assert.Equal(t, "main.mk:4:18", program.End().String())
+
+	let := program.Statements[0].(*ast.LetStatement)
+	assert.Equal(t, "main.mk:1:11", let.Value.Pos().String())
+	assert.Equal(t, "main.mk:3:2", let.Value.End().String())
+
+	body := let.Value.(*ast.FunctionLiteral).Body.Statements[0]
+	assert.Equal(t, "main.mk:2:2", body.Pos().String())
+	assert.Equal(t, "main.mk:2:7", body.End().String())
+
+	call := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+	assert.Equal(t, "main.mk:4:1", call.Pos().String())
+	assert.Equal(t, "main.mk:4:8", call.Arguments[1].Pos().String())
+	assert.Equal(t, "main.mk:4:17", call.Arguments[1].End().String())
+}
+
+func Test_Comments(t *testing.T) {
+	input := `// the answer
+let x = 42; /* inline */ x; // done`
+
+	l := lexer.NewLexer(input, lexer.WithComments())
+	p := NewParser(l)
+
+	program := p.Parse()
+
+	assert.Len(t, p.errors, 0)
+	assert.Len(t, program.Statements, 2)
+	assert.Equal(t, "let x = 42;x", program.String())
+}
+
+func Test_ParserErrorPositions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let = 5;", "main.mk:1:5: expected next token to be IDENT, got = instead"},
+		{"let x = 5;\nlet y 5;", "main.mk:2:7: expected next token to be =, got INT instead"},
+		{"5 + ;", "main.mk:1:5: no prefix parse function for ; found"},
+		{`let s = "a\qb";`, `main.mk:1:11: invalid escape sequence \q`},
+	}
+
+	for _, test := range tests {
+		l := lexer.NewLexer(test.input, lexer.WithFilename("main.mk"))
+		p := NewParser(l)
+
+		p.Parse()
+
+		assert.NotEmpty(t, p.Errors())
+		assert.Equal(t, test.expected, p.Errors()[0])
+	}
+}
@@ -1,21 +1,32 @@
 package token
 
+import "fmt"
+
 const (
 	ILLEGAL = "ILLEGAL"
 	EOF     = "EOF"
 
 	IDENT  = "IDENT"
 	INT    = "INT"
+	FLOAT  = "FLOAT"
 	STRING = "STRING"
 
-	ASSIGN = "="
+	ASSIGN          = "="
+	PLUS_ASSIGN     = "+="
+	MINUS_ASSIGN    = "-="
+	ASTERISK_ASSIGN = "*="
+	SLASH_ASSIGN    = "/="
 
 	PLUS     = "+"
 	MINUS    = "-"
 	ASTERISK = "*"
 	SLASH    = "/"
+	PERCENT  = "%"
+	POWER    = "**"
 	LT       = "<"
 	GT       = ">"
+	LT_EQ    = "<="
+	GT_EQ    = ">="
 
 	LBRACKET = "["
 	RBRACKET = "]"
@@ -32,26 +43,69 @@ const (
 	EQ     = "=="
 	NOT_EQ = "!="
 
-	COLON = ":"
+	AND  = "&&"
+	OR   = "||"
+	PIPE = "|>"
+
+	QUESTION       = "?"
+	NULLISH        = "??"
+	OPTIONAL_CHAIN = "?."
+
+	AMPERSAND   = "&"
+	BAR         = "|"
+	CARET       = "^"
+	TILDE       = "~"
+	SHIFT_LEFT  = "<<"
+	SHIFT_RIGHT = ">>"
+
+	COLON    = ":"
+	DOT      = "."
+	ELLIPSIS = "..."
+	ARROW    = "=>"
+
+	RANGE           = ".."
+	RANGE_INCLUSIVE = "..="
 
 	// keywords
 	FUNCTION = "FUNCTION"
 	LET      = "LET"
+	CONST    = "CONST"
 	TRUE     = "TRUE"
 	FALSE    = "FALSE"
 	IF       = "IF"
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
+	WHILE    = "WHILE"
+	FOR      = "FOR"
+	IN       = "IN"
+	BREAK    = "BREAK"
+	CONTINUE = "CONTINUE"
+	MATCH    = "MATCH"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	THROW    = "THROW"
 )
 
 var keywords = map[string]TokenType{
-	"let":    LET,
-	"fn":     FUNCTION,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
+	"let":      LET,
+	"const":    CONST,
+	"fn":       FUNCTION,
+	"true":     TRUE,
+	"false":    FALSE,
+	"if":       IF,
+	"else":     ELSE,
+	"return":   RETURN,
+	"while":    WHILE,
+	"for":      FOR,
+	"in":       IN,
+	"break":    BREAK,
+	"continue": CONTINUE,
+	"match":    MATCH,
+	"try":      TRY,
+	"catch":    CATCH,
+	"finally":  FINALLY,
+	"throw":    THROW,
 }
 
 func LookupIdent(identifier string) TokenType {
@@ -64,11 +118,53 @@ func LookupIdent(identifier string) TokenType {
 
 type TokenType string
 
+// Position describes a location in the source. Line and Column start at 1,
+// Offset is the byte offset from the start of the input.
+type Position struct {
+	Filename string
+	Offset   int
+	Line     int
+	Column   int
+}
+
+// IsValid reports whether the position has been set by the lexer.
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+// String formats the position as file:line:col, omitting the file name when
+// it is not known.
+func (p Position) String() string {
+	if !p.IsValid() {
+		return "-"
+	}
+
+	if p.Filename == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+
+	return fmt.Sprintf("%s:%d:%d", p.Filename, p.Line, p.Column)
+}
+
+// Comment is a line (//) or block (/* */) comment. Text includes the
+// comment delimiters.
+type Comment struct {
+	Text string
+	Pos  Position
+	End  Position
+}
+
 type Token struct {
 	Type    TokenType
 	Literal string
+	Pos     Position // position of the first character of the token
+	End     Position // position immediately after the token
+
+	// Comments preceding the token, only retained when the lexer is
+	// configured to keep them.
+	Comments []Comment
 }
 
-func NewToken(tokenType TokenType, character byte) Token {
+func NewToken(tokenType TokenType, character rune) Token {
 	return Token{Type: tokenType, Literal: string(character)}
 }